@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"sdtop/internal/export"
+	"sdtop/internal/systemd"
+)
+
+// runGraph implements `sdtop graph [flags] [unit]`, which prints the
+// dependency and ordering graph of a unit, or of the whole system, as DOT
+// or Mermaid. It returns the exit status.
+func runGraph(args []string) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdtop graph [flags] [unit]")
+		fmt.Fprintln(fs.Output(), "\nExport the dependency graph of a unit or target, or of all loaded units without one.")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "output format: dot or mermaid (default from -o's extension, else dot)")
+	output := fs.String("o", "", "write to this file instead of standard output")
+	depth := fs.Int("depth", 2, "edges followed away from the unit, 0 for no limit")
+	edges := fs.String("edges", "requires,wants,after,before,conflicts", "comma-separated edge types to include")
+	excludeSystem := fs.Bool("exclude-system", false, "leave out devices, mounts, slices, scopes, early-boot targets and systemd-* units")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	relations, err := systemd.ParseRelations(*edges)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --edges: %v\n", err)
+		return 2
+	}
+
+	f := export.GraphFormatFromPath(*output)
+	if *format != "" {
+		if f, err = export.ParseGraphFormat(*format); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --format: %v\n", err)
+			return 2
+		}
+	}
+
+	manager, err := systemd.NewManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to systemd: %v\n", err)
+		return 1
+	}
+	defer manager.Close()
+
+	opts := systemd.GraphOptions{Depth: *depth, Relations: relations, ExcludeSystem: *excludeSystem}
+	graph, err := manager.GetUnitGraph(fs.Arg(0), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build the graph: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		err = export.WriteGraph(os.Stdout, graph, f)
+	} else {
+		err = export.WriteGraphFile(*output, graph, f)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write the graph: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+)
+
+// runLint implements `sdtop lint <unit|file>`, which checks a unit file
+// and its drop-ins. It returns 1 when errors are found.
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdtop lint <unit|file>")
+		fmt.Fprintln(fs.Output(), "\nCheck a loaded unit's file and drop-ins, or a unit file and the drop-ins in <file>.d/.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	target := fs.Arg(0)
+
+	// Paths are linted as files, anything else as a loaded unit
+	var files []string
+	if _, err := os.Stat(target); err == nil || strings.Contains(target, "/") {
+		files = systemd.LocalUnitFilePaths(target)
+	} else {
+		manager, err := systemd.NewManager()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to connect to systemd: %v\n", err)
+			return 1
+		}
+		defer manager.Close()
+
+		if files, err = manager.UnitFilePaths(target); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to find the unit file of %s: %v\n", target, err)
+			return 1
+		}
+	}
+
+	findings, err := systemd.LintUnitFiles(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to lint %s: %v\n", target, err)
+		return 1
+	}
+
+	status := 0
+	for _, f := range findings {
+		if f.Line > 0 {
+			fmt.Printf("%s:%d: %s: %s\n", f.File, f.Line, f.Severity, f.Message)
+		} else {
+			fmt.Printf("%s: %s: %s\n", f.File, f.Severity, f.Message)
+		}
+		if f.Severity == types.LintError {
+			status = 1
+		}
+	}
+	if len(findings) == 0 {
+		fmt.Printf("%s: no problems found in %d file(s)\n", target, len(files))
+	}
+	return status
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"strings"
+	"time"
 
+	"sdtop/internal/config"
 	"sdtop/internal/systemd"
 	"sdtop/internal/ui"
 
@@ -11,6 +17,60 @@ import (
 )
 
 func main() {
+	// Subcommands run without the UI
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "graph":
+			os.Exit(runGraph(os.Args[2:]))
+		case "lint":
+			os.Exit(runLint(os.Args[2:]))
+		case "port":
+			os.Exit(runPort(os.Args[2:]))
+		}
+	}
+
+	// Load user configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	themeName := flag.String("theme", "", fmt.Sprintf("color theme (%s)", strings.Join(ui.ThemeNames(cfg), ", ")))
+	boot := flag.String("boot", "all", "show logs from one boot: all, current, previous, an offset like -2, or a boot ID")
+	since := flag.String("since", "", "show logs at or after this time (e.g. \"2024-05-01 13:00\", yesterday, -1h)")
+	until := flag.String("until", "", "show logs before this time (same formats as --since)")
+	logBuffer := flag.Int("log-buffer", cfg.LogBufferSize, "maximum log entries kept in memory when scrolling back")
+	flag.Parse()
+
+	// Parse the log time range
+	opts := ui.Options{
+		Boot:          *boot,
+		LogBufferSize: *logBuffer,
+		FlapStarts:    cfg.FlapStarts,
+		FlapWindow:    time.Duration(cfg.FlapWindowMinutes) * time.Minute,
+	}
+	now := time.Now()
+	if *since != "" {
+		if opts.Since, err = systemd.ParseTimeSpec(*since, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --since: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *until != "" {
+		if opts.Until, err = systemd.ParseTimeSpec(*until, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --until: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Pick the color theme before anything is rendered
+	opts.Theme, err = ui.ResolveTheme(cfg, *themeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load theme: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create systemd manager
 	manager, err := systemd.NewManager()
 	if err != nil {
@@ -28,7 +88,7 @@ func main() {
 	defer logReader.Close()
 
 	// Create UI model
-	model, err := ui.NewModel(manager, logReader)
+	model, err := ui.NewModel(manager, logReader, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create UI: %v\n", err)
 		os.Exit(1)
@@ -41,8 +101,14 @@ func main() {
 		tea.WithMouseCellMotion(),
 	)
 
+	// Libraries (e.g. go-systemd's signal subscription) log to stderr,
+	// which would draw over the UI
+	log.SetOutput(io.Discard)
+
 	// Run the program
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	log.SetOutput(os.Stderr)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
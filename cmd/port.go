@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"sdtop/internal/systemd"
+)
+
+// runPort implements `sdtop port <port>`, which shows the processes and
+// units listening on a port. It returns 1 when nothing listens.
+func runPort(args []string) int {
+	fs := flag.NewFlagSet("port", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdtop port <port>")
+		fmt.Fprintln(fs.Output(), "\nShow which processes and units listen on a TCP or UDP port.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	port, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || port < 1 || port > 65535 {
+		fmt.Fprintf(os.Stderr, "%q is not a port number\n", fs.Arg(0))
+		return 2
+	}
+
+	report, err := systemd.NewProcessManager().ListeningOn(port)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read sockets: %v\n", err)
+		return 1
+	}
+
+	for _, s := range report.Sockets {
+		unit := s.Unit
+		if unit == "" {
+			unit = "-"
+		}
+		fmt.Printf("%-5s %-24s %-8d %-16s %s\n", s.Protocol, s.Local, s.PID, s.Process, unit)
+	}
+	if report.Unreadable > 0 {
+		fmt.Fprintf(os.Stderr, "%d processes couldn't be inspected; run as root to see all sockets\n", report.Unreadable)
+	}
+	if len(report.Sockets) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing listens on port %d\n", port)
+		return 1
+	}
+	return 0
+}
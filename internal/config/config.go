@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user settings loaded from the config file
+type Config struct {
+	// Theme is the name of a built-in or custom theme ("auto" picks
+	// dark or light based on the terminal background)
+	Theme string `json:"theme"`
+
+	// Themes defines custom themes by name
+	Themes map[string]ThemeConfig `json:"themes"`
+
+	// LogBufferSize caps how many log entries are kept in memory while
+	// scrolling back through history
+	LogBufferSize int `json:"log_buffer_size"`
+
+	// FlapStarts and FlapWindowMinutes flag a service as flapping when it
+	// starts more than FlapStarts times within FlapWindowMinutes
+	FlapStarts        int `json:"flap_starts"`
+	FlapWindowMinutes int `json:"flap_window_minutes"`
+}
+
+// ThemeConfig describes a custom theme. Colors accept anything lipgloss
+// understands: ANSI numbers ("196") or hex values ("#ff0000"). Empty
+// fields are inherited from Base.
+type ThemeConfig struct {
+	Base       string `json:"base"`
+	Text       string `json:"text"`
+	Muted      string `json:"muted"`
+	Accent     string `json:"accent"`
+	Success    string `json:"success"`
+	Warning    string `json:"warning"`
+	Error      string `json:"error"`
+	Background string `json:"background"`
+	Border     string `json:"border"`
+
+	// Palette colors unit tags in merged log views
+	Palette []string `json:"palette"`
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return &Config{
+		Theme:             "auto",
+		LogBufferSize:     5000,
+		FlapStarts:        3,
+		FlapWindowMinutes: 10,
+	}
+}
+
+// Path returns the location of the config file
+// ($XDG_CONFIG_HOME/sdtop/config.json or ~/.config/sdtop/config.json)
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sdtop", "config.json")
+}
+
+// Load reads the config file, falling back to defaults if it doesn't exist
+func Load() (*Config, error) {
+	cfg := Default()
+
+	path := Path()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
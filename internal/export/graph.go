@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// GraphFormat is a unit graph file format
+type GraphFormat int
+
+const (
+	GraphDOT     GraphFormat = iota // Graphviz DOT
+	GraphMermaid                    // Mermaid flowchart
+)
+
+// String returns the format's name
+func (f GraphFormat) String() string {
+	if f == GraphMermaid {
+		return "Mermaid"
+	}
+	return "DOT"
+}
+
+// ParseGraphFormat parses a graph format name ("dot" or "mermaid")
+func ParseGraphFormat(s string) (GraphFormat, error) {
+	switch strings.ToLower(s) {
+	case "dot", "gv", "graphviz":
+		return GraphDOT, nil
+	case "mermaid", "mmd":
+		return GraphMermaid, nil
+	}
+	return 0, fmt.Errorf("unknown graph format %q (want dot or mermaid)", s)
+}
+
+// GraphFormatFromPath picks the graph format from a file extension:
+// .mmd/.mermaid/.md for Mermaid and DOT otherwise
+func GraphFormatFromPath(path string) GraphFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mmd", ".mermaid", ".md":
+		return GraphMermaid
+	default:
+		return GraphDOT
+	}
+}
+
+// Edge colors follow systemd-analyze dot
+var dotEdgeColors = map[string]string{
+	"Requires":  "black",
+	"Wants":     "grey66",
+	"After":     "green",
+	"Before":    "green",
+	"Conflicts": "red",
+}
+
+// dotStateColors fill units by their active state
+var dotStateColors = map[string]string{
+	"active":       "palegreen",
+	"failed":       "lightcoral",
+	"activating":   "khaki",
+	"deactivating": "khaki",
+	"reloading":    "khaki",
+}
+
+// WriteGraphFile writes a unit graph to a file in the given format
+func WriteGraphFile(path string, g *types.UnitGraph, f GraphFormat) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteGraph(file, g, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteGraph writes a unit graph to w in the given format
+func WriteGraph(w io.Writer, g *types.UnitGraph, f GraphFormat) error {
+	bw := bufio.NewWriter(w)
+	if f == GraphMermaid {
+		writeMermaid(bw, g)
+	} else {
+		writeDOT(bw, g)
+	}
+	return bw.Flush()
+}
+
+// graphUnits returns the units of a graph sorted by name
+func graphUnits(g *types.UnitGraph) []string {
+	units := make([]string, 0, len(g.States))
+	for unit := range g.States {
+		units = append(units, unit)
+	}
+	for _, edge := range g.Edges {
+		if _, ok := g.States[edge.From]; !ok {
+			units = append(units, edge.From)
+		}
+		if _, ok := g.States[edge.To]; !ok {
+			units = append(units, edge.To)
+		}
+	}
+	sort.Strings(units)
+
+	// Drop the duplicates of units missing from States
+	unique := units[:0]
+	for i, unit := range units {
+		if i == 0 || unit != units[i-1] {
+			unique = append(unique, unit)
+		}
+	}
+	return unique
+}
+
+// writeDOT writes a graph like systemd-analyze dot, with units filled by
+// their state and edges colored and labeled by relation
+func writeDOT(w *bufio.Writer, g *types.UnitGraph) {
+	name := g.Root
+	if name == "" {
+		name = "system"
+	}
+
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote(name))
+	w.WriteString("\trankdir=LR;\n")
+	w.WriteString("\tnode [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=\"sans-serif\"];\n")
+	w.WriteString("\tedge [fontname=\"sans-serif\", fontsize=10];\n\n")
+
+	for _, unit := range graphUnits(g) {
+		attrs := []string{}
+		if color, ok := dotStateColors[g.States[unit]]; ok {
+			attrs = append(attrs, "fillcolor="+color)
+		}
+		if unit == g.Root {
+			attrs = append(attrs, "penwidth=2")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(w, "\t%s [%s];\n", dotQuote(unit), strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(w, "\t%s;\n", dotQuote(unit))
+		}
+	}
+	w.WriteString("\n")
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(w, "\t%s -> %s [label=%s, color=%s];\n",
+			dotQuote(edge.From), dotQuote(edge.To),
+			dotQuote(strings.ToLower(edge.Relation)), dotEdgeColors[edge.Relation])
+	}
+	w.WriteString("}\n")
+}
+
+// dotQuote quotes a DOT ID. Only double quotes are escaped; anything else,
+// including the \x2d escapes in unit names, is kept as is.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// writeMermaid writes a graph as a Mermaid flowchart. Units get numbered
+// IDs since unit names contain characters Mermaid doesn't allow in IDs;
+// ordering edges are dotted and conflicts end in a cross.
+func writeMermaid(w *bufio.Writer, g *types.UnitGraph) {
+	w.WriteString("flowchart LR\n")
+
+	ids := map[string]string{}
+	classes := map[string][]string{}
+	for i, unit := range graphUnits(g) {
+		id := fmt.Sprintf("u%d", i)
+		ids[unit] = id
+		fmt.Fprintf(w, "\t%s[\"%s\"]\n", id, strings.ReplaceAll(unit, `"`, "#quot;"))
+		if state := g.States[unit]; state != "" {
+			classes[state] = append(classes[state], id)
+		}
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Relation {
+		case "After", "Before":
+			arrow = "-.->"
+		case "Conflicts":
+			arrow = "--x"
+		}
+		fmt.Fprintf(w, "\t%s %s|%s| %s\n", ids[edge.From], arrow, strings.ToLower(edge.Relation), ids[edge.To])
+	}
+
+	states := make([]string, 0, len(classes))
+	for state := range classes {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		if color, ok := dotStateColors[state]; ok {
+			fmt.Fprintf(w, "\tclassDef %s fill:%s\n", state, color)
+			fmt.Fprintf(w, "\tclass %s %s\n", strings.Join(classes[state], ","), state)
+		}
+	}
+	if id, ok := ids[g.Root]; ok {
+		fmt.Fprintf(w, "\tstyle %s stroke-width:3px\n", id)
+	}
+}
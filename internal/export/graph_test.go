@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+func TestDotQuote(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"nginx.service", `"nginx.service"`},
+		{`dev-disk-by\x2dlabel-root.device`, `"dev-disk-by\x2dlabel-root.device"`},
+		{`say "hi".service`, `"say \"hi\".service"`},
+		{"mnt-données.mount", `"mnt-données.mount"`},
+		{"", `""`},
+	}
+
+	for _, tt := range tests {
+		if got := dotQuote(tt.id); got != tt.want {
+			t.Errorf("dotQuote(%q) = %s, want %s", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestWriteGraphDOT(t *testing.T) {
+	g := &types.UnitGraph{
+		Root:   "app.service",
+		States: map[string]string{"app.service": "active", `dev-disk-by\x2dlabel-data.device`: "failed"},
+		Edges: []types.UnitEdge{
+			{From: "app.service", To: `dev-disk-by\x2dlabel-data.device`, Relation: "Requires"},
+			{From: "app.service", To: "network.target", Relation: "After"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGraph(&buf, g, GraphDOT); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`digraph "app.service" {`,
+		`"app.service" [`,
+		"penwidth=2",
+		`"app.service" -> "dev-disk-by\x2dlabel-data.device" [label="requires", color=black];`,
+		`"app.service" -> "network.target" [label="after", color=green];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `\\`) {
+		t.Errorf("backslashes doubled in:\n%s", out)
+	}
+}
+
+func TestGraphFormatFromPath(t *testing.T) {
+	tests := map[string]GraphFormat{
+		"deps.dot":      GraphDOT,
+		"deps.gv":       GraphDOT,
+		"deps":          GraphDOT,
+		"deps.mmd":      GraphMermaid,
+		"README.MD":     GraphMermaid,
+		"deps.mermaid":  GraphMermaid,
+		"/tmp/deps.svg": GraphDOT,
+	}
+	for path, want := range tests {
+		if got := GraphFormatFromPath(path); got != want {
+			t.Errorf("GraphFormatFromPath(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// Format is a log export file format
+type Format int
+
+const (
+	FormatText    Format = iota // journalctl -o short-iso style lines
+	FormatJSON                  // One JSON object per line (journalctl -o json)
+	FormatJournal               // Journal export format (journalctl -o export)
+)
+
+// String returns the format's name
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "JSON lines"
+	case FormatJournal:
+		return "journal export"
+	default:
+		return "text"
+	}
+}
+
+// FormatFromPath picks the export format from a file extension:
+// .json/.jsonl for JSON lines, .export/.journal for the journal export
+// format and plain text otherwise
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		return FormatJSON
+	case ".export", ".journal":
+		return FormatJournal
+	default:
+		return FormatText
+	}
+}
+
+// DefaultFileName builds a file name for an export in the current
+// directory, e.g. sdtop-nginx-20240501-130000.log
+func DefaultFileName(prefix, unit, ext string) string {
+	name := strings.TrimSuffix(unit, filepath.Ext(unit))
+	return fmt.Sprintf("%s-%s-%s%s", prefix, name, time.Now().Format("20060102-150405"), ext)
+}
+
+// WriteLogsFile writes log entries to a file in the given format
+func WriteLogsFile(path string, logs []types.LogEntry, f Format) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteLogs(file, logs, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteLogs writes log entries to w in the given format
+func WriteLogs(w io.Writer, logs []types.LogEntry, f Format) error {
+	bw := bufio.NewWriter(w)
+
+	for _, entry := range logs {
+		var err error
+		switch f {
+		case FormatJSON:
+			err = writeJSON(bw, entry)
+		case FormatJournal:
+			err = writeJournalExport(bw, entry)
+		default:
+			err = writeText(bw, entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeText writes an entry like journalctl -o short-iso:
+// "2024-05-01T13:00:00+0200 host nginx[1234]: message"
+func writeText(w *bufio.Writer, entry types.LogEntry) error {
+	ident := entry.Fields["SYSLOG_IDENTIFIER"]
+	if ident == "" {
+		ident = entry.Fields["_COMM"]
+	}
+	if pid := entry.Fields["_PID"]; pid != "" {
+		ident += "[" + pid + "]"
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s %s: %s\n",
+		entry.Timestamp.Format("2006-01-02T15:04:05-0700"),
+		entry.Fields["_HOSTNAME"],
+		ident,
+		entry.Message,
+	)
+	return err
+}
+
+// writeJSON writes an entry's fields as one JSON object per line
+func writeJSON(w *bufio.Writer, entry types.LogEntry) error {
+	data, err := json.Marshal(entry.Fields)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// writeJournalExport writes an entry in the journal export format, which
+// systemd-journal-remote and journalctl --file can import. Address fields
+// come first; values with newlines or control characters use the binary
+// form (name, newline, little-endian 64-bit length, data).
+func writeJournalExport(w *bufio.Writer, entry types.LogEntry) error {
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ai, aj := strings.HasPrefix(keys[i], "__"), strings.HasPrefix(keys[j], "__")
+		if ai != aj {
+			return ai
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		value := entry.Fields[key]
+		if !needsBinary(value) {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", key, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+		w.WriteString(key)
+		w.WriteByte('\n')
+		w.Write(size[:])
+		w.WriteString(value)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	// A blank line ends the entry
+	return w.WriteByte('\n')
+}
+
+// needsBinary reports whether a field value must use the binary form
+func needsBinary(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < ' ' && value[i] != '\t' {
+			return true
+		}
+	}
+	return false
+}
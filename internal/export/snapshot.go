@@ -0,0 +1,220 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// Snapshot is the state of a unit bundled for an incident ticket
+type Snapshot struct {
+	Unit       string
+	Taken      time.Time
+	Properties map[string]interface{}
+	Processes  []*types.Process
+	Logs       []types.LogEntry
+}
+
+// WriteSnapshot writes a snapshot as a .tar.gz archive containing the
+// unit's status, properties, unit file and drop-ins, process tree and
+// recent logs (as text and JSON lines). The archive is written to a
+// temporary file next to path and renamed once complete, so a failure
+// doesn't leave a truncated snapshot behind.
+func WriteSnapshot(path string, s *Snapshot) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := file.Name()
+
+	err = writeArchive(file, s)
+	if err == nil {
+		err = file.Chmod(0o644)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// writeArchive writes the files of a snapshot as a .tar.gz stream
+func writeArchive(w io.Writer, s *Snapshot) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	dir := fmt.Sprintf("%s-%s", s.Unit, s.Taken.Format("20060102-150405"))
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    dir + "/" + name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: s.Taken,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := add("status.txt", []byte(FormatStatus(s.Unit, s.Properties))); err != nil {
+		return err
+	}
+	if err := add("properties.txt", []byte(formatProperties(s.Properties))); err != nil {
+		return err
+	}
+
+	// The unit file and its drop-ins, as loaded by systemd
+	for _, unitFile := range unitFiles(s.Properties) {
+		data, err := os.ReadFile(unitFile)
+		if err != nil {
+			data = []byte(fmt.Sprintf("# could not read %s: %v\n", unitFile, err))
+		}
+		if err := add("unit"+unitFile, data); err != nil {
+			return err
+		}
+	}
+
+	var procs strings.Builder
+	writeProcessTree(&procs, s.Processes, "")
+	if err := add("processes.txt", []byte(procs.String())); err != nil {
+		return err
+	}
+
+	var text, jsonl bytes.Buffer
+	if err := WriteLogs(&text, s.Logs, FormatText); err != nil {
+		return err
+	}
+	if err := WriteLogs(&jsonl, s.Logs, FormatJSON); err != nil {
+		return err
+	}
+	if err := add("logs.txt", text.Bytes()); err != nil {
+		return err
+	}
+	if err := add("logs.json", jsonl.Bytes()); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// FormatStatus summarizes a unit's properties like `systemctl status`
+func FormatStatus(unit string, props map[string]interface{}) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "● %s - %s\n", unit, prop(props, "Description"))
+	fmt.Fprintf(&sb, "     Loaded: %s (%s; %s; preset: %s)\n",
+		prop(props, "LoadState"), prop(props, "FragmentPath"),
+		prop(props, "UnitFileState"), prop(props, "UnitFilePreset"))
+
+	active := fmt.Sprintf("%s (%s)", prop(props, "ActiveState"), prop(props, "SubState"))
+	if since := usecProp(props, "ActiveEnterTimestamp"); !since.IsZero() {
+		active += " since " + since.Format("Mon 2006-01-02 15:04:05 MST")
+	}
+	fmt.Fprintf(&sb, "     Active: %s\n", active)
+
+	if pid := prop(props, "MainPID"); pid != "" && pid != "0" {
+		fmt.Fprintf(&sb, "   Main PID: %s\n", pid)
+	}
+	if tasks, ok := uintProp(props, "TasksCurrent"); ok {
+		fmt.Fprintf(&sb, "      Tasks: %d\n", tasks)
+	}
+	if mem, ok := uintProp(props, "MemoryCurrent"); ok {
+		fmt.Fprintf(&sb, "     Memory: %.1fM\n", float64(mem)/(1024*1024))
+	}
+	if cpu, ok := uintProp(props, "CPUUsageNSec"); ok {
+		fmt.Fprintf(&sb, "        CPU: %s\n", time.Duration(cpu).Round(time.Millisecond))
+	}
+	if result := prop(props, "Result"); result != "" && result != "success" {
+		fmt.Fprintf(&sb, "     Result: %s\n", result)
+	}
+
+	return sb.String()
+}
+
+// formatProperties lists properties like `systemctl show`
+func formatProperties(props map[string]interface{}) string {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "%s=%s\n", key, prop(props, key))
+	}
+	return sb.String()
+}
+
+// unitFiles returns the unit file and drop-in paths of a unit
+func unitFiles(props map[string]interface{}) []string {
+	var files []string
+	if path, ok := props["FragmentPath"].(string); ok && path != "" {
+		files = append(files, path)
+	}
+	if dropIns, ok := props["DropInPaths"].([]string); ok {
+		files = append(files, dropIns...)
+	}
+	return files
+}
+
+// writeProcessTree writes a plain-text process tree
+func writeProcessTree(sb *strings.Builder, procs []*types.Process, prefix string) {
+	for _, proc := range procs {
+		fmt.Fprintf(sb, "%s[%d] %s: %s\n", prefix, proc.PID, proc.Name, proc.Cmdline)
+		writeProcessTree(sb, proc.Children, prefix+"  ")
+	}
+}
+
+// prop formats a property value for display
+func prop(props map[string]interface{}, key string) string {
+	switch v := props[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// uintProp returns a numeric property, treating systemd's "unset" value
+// (the maximum uint64) as missing
+func uintProp(props map[string]interface{}, key string) (uint64, bool) {
+	v, ok := props[key].(uint64)
+	if !ok || v == math.MaxUint64 {
+		return 0, false
+	}
+	return v, true
+}
+
+// usecProp returns a timestamp property given in microseconds
+func usecProp(props map[string]interface{}, key string) time.Time {
+	v, ok := uintProp(props, key)
+	if !ok || v == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(v))
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	unitFile := filepath.Join(dir, "app.service")
+	if err := os.WriteFile(unitFile, []byte("[Service]\nExecStart=/usr/bin/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Snapshot{
+		Unit:  "app.service",
+		Taken: time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC),
+		Properties: map[string]interface{}{
+			"Description":  "Example app",
+			"ActiveState":  "active",
+			"FragmentPath": unitFile,
+		},
+		Processes: []*types.Process{{PID: 42, Name: "app", Cmdline: "/usr/bin/app"}},
+		Logs:      []types.LogEntry{{Message: "started"}},
+	}
+	path := filepath.Join(dir, "snapshot.tar.gz")
+	if err := WriteSnapshot(path, s); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(data)
+	}
+
+	prefix := "app.service-20240510-153000/"
+	for _, name := range []string{"status.txt", "properties.txt", "unit" + unitFile, "processes.txt", "logs.txt", "logs.json"} {
+		if _, ok := files[prefix+name]; !ok {
+			names := make([]string, 0, len(files))
+			for n := range files {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			t.Errorf("missing %s; archive has %v", prefix+name, names)
+		}
+	}
+	if got := files[prefix+"unit"+unitFile]; !strings.Contains(got, "ExecStart=/usr/bin/app") {
+		t.Errorf("unit file copied as %q", got)
+	}
+	if got := files[prefix+"processes.txt"]; !strings.Contains(got, "42") {
+		t.Errorf("processes.txt is %q", got)
+	}
+
+	// Only the snapshot is left in the directory
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		for _, e := range entries {
+			t.Log(e.Name())
+		}
+		t.Errorf("%d files in the directory, want the unit file and the snapshot", len(entries))
+	}
+}
+
+func TestWriteSnapshotFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "snapshot.tar.gz")
+	if err := WriteSnapshot(path, &Snapshot{Unit: "app.service"}); err == nil {
+		t.Fatal("writing into a missing directory succeeded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("snapshot left behind: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package systemd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// AnalyzeBoot reproduces systemd-analyze time, blame and critical-chain
+// from the monotonic timestamps systemd records for the manager and for
+// every loaded unit
+func (m *Manager) AnalyzeBoot() (*types.BootAnalysis, error) {
+	stamps := map[string]time.Duration{}
+	for _, name := range []string{"Firmware", "Loader", "InitRD", "Userspace", "Finish"} {
+		value, err := m.conn.GetManagerProperty(name + "TimestampMonotonic")
+		if err != nil {
+			return nil, err
+		}
+		stamps[name] = parseVariantUsec(value)
+	}
+
+	a := &types.BootAnalysis{
+		Firmware: stamps["Firmware"] - stamps["Loader"],
+		Loader:   stamps["Loader"],
+		Kernel:   stamps["Userspace"],
+		Finished: stamps["Finish"],
+	}
+	if stamps["InitRD"] > 0 {
+		a.Kernel = stamps["InitRD"]
+		a.InitRD = stamps["Userspace"] - stamps["InitRD"]
+	}
+	if a.Finished > 0 {
+		a.Userspace = a.Finished - stamps["Userspace"]
+	}
+
+	units, err := m.conn.ListUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only unit properties are needed, so the type-specific ones that
+	// GetUnitProperties adds are skipped
+	timings := map[string]types.UnitTiming{}
+	after := map[string][]string{}
+	for _, unit := range units {
+		props, err := m.conn.GetUnitProperties(unit.Name)
+		if err != nil {
+			continue
+		}
+		after[unit.Name], _ = props["After"].([]string)
+
+		t := types.UnitTiming{
+			Unit:       unit.Name,
+			Activating: monotonicProp(props, "InactiveExitTimestampMonotonic"),
+			Activated:  monotonicProp(props, "ActiveEnterTimestampMonotonic"),
+		}
+		// Units started after boot finished (or restarted since) don't
+		// tell anything about the boot
+		if t.Activated == 0 || (a.Finished > 0 && t.Activated > a.Finished) {
+			continue
+		}
+		if t.Activating > 0 && t.Activated > t.Activating {
+			t.Time = t.Activated - t.Activating
+		}
+		timings[unit.Name] = t
+	}
+
+	for _, t := range timings {
+		a.Units = append(a.Units, t)
+	}
+	sort.Slice(a.Units, func(i, j int) bool {
+		if a.Units[i].Activating != a.Units[j].Activating {
+			return a.Units[i].Activating < a.Units[j].Activating
+		}
+		return a.Units[i].Unit < a.Units[j].Unit
+	})
+
+	// The default target is an alias, its Id is the real unit
+	if props, err := m.conn.GetUnitProperties("default.target"); err == nil {
+		a.DefaultTarget, _ = props["Id"].(string)
+	}
+	a.Chain = criticalChain(a.DefaultTarget, timings, after)
+
+	return a, nil
+}
+
+// criticalChain follows, from a unit, the After dependency that became
+// active last, which is the one the unit waited for, like
+// systemd-analyze critical-chain
+func criticalChain(unit string, timings map[string]types.UnitTiming, after map[string][]string) []types.UnitTiming {
+	var chain []types.UnitTiming
+	seen := map[string]bool{}
+
+	for unit != "" && !seen[unit] {
+		seen[unit] = true
+		t, ok := timings[unit]
+		if !ok {
+			break
+		}
+		chain = append(chain, t)
+
+		next := ""
+		var latest time.Duration
+		for _, dep := range after[unit] {
+			d, ok := timings[dep]
+			if !ok || d.Activated > t.Activated || d.Activated <= latest {
+				continue
+			}
+			next, latest = dep, d.Activated
+		}
+		unit = next
+	}
+	return chain
+}
+
+// monotonicProp returns a monotonic timestamp property given in microseconds
+func monotonicProp(props map[string]interface{}, key string) time.Duration {
+	usec, _ := props[key].(uint64)
+	return time.Duration(usec) * time.Microsecond
+}
+
+// parseVariantUsec parses a microsecond value in GVariant text form, as
+// returned for manager properties ("@t 1234567")
+func parseVariantUsec(s string) time.Duration {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	usec, _ := strconv.ParseUint(fields[len(fields)-1], 10, 64)
+	return time.Duration(usec) * time.Microsecond
+}
@@ -0,0 +1,66 @@
+package systemd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+func TestCriticalChain(t *testing.T) {
+	timings := map[string]types.UnitTiming{}
+	for unit, activated := range map[string]int{
+		"graphical.target": 10,
+		"app.service":      8,
+		"db.service":       5,
+		"network.target":   3,
+		"late.service":     12, // Became active after the target, so it wasn't waited for
+		"a.service":        2,
+		"b.service":        2,
+	} {
+		timings[unit] = types.UnitTiming{Unit: unit, Activated: time.Duration(activated) * time.Second}
+	}
+	after := map[string][]string{
+		"graphical.target": {"db.service", "app.service", "late.service", "missing.service"},
+		"app.service":      {"network.target", "db.service"},
+		"db.service":       {"network.target"},
+		"a.service":        {"b.service"},
+		"b.service":        {"a.service"},
+	}
+
+	tests := []struct {
+		unit string
+		want []string
+	}{
+		{"graphical.target", []string{"graphical.target", "app.service", "db.service", "network.target"}},
+		{"network.target", []string{"network.target"}},
+		{"missing.service", []string{}},
+		// Ordering cycles end the chain instead of looping
+		{"a.service", []string{"a.service", "b.service"}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for _, timing := range criticalChain(tt.unit, timings, after) {
+			got = append(got, timing.Unit)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("criticalChain(%s) = %v, want %v", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestParseVariantUsec(t *testing.T) {
+	tests := map[string]time.Duration{
+		"@t 1234567": 1234567 * time.Microsecond,
+		"42":         42 * time.Microsecond,
+		"":           0,
+		"@t junk":    0,
+	}
+	for in, want := range tests {
+		if got := parseVariantUsec(in); got != want {
+			t.Errorf("parseVariantUsec(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package systemd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// ListBoots returns the boots recorded in the journal, oldest first,
+// with offsets relative to the current boot like `journalctl --list-boots`
+func (lr *LogReader) ListBoots() ([]types.Boot, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	ids, err := lr.journal.GetUniqueValues(sdjournal.SD_JOURNAL_FIELD_BOOT_ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list boots: %w", err)
+	}
+
+	var boots []types.Boot
+	for _, id := range ids {
+		first, last, err := lr.bootRange(id)
+		if err != nil {
+			continue
+		}
+		boots = append(boots, types.Boot{ID: id, First: first, Last: last})
+	}
+
+	sort.Slice(boots, func(i, j int) bool {
+		return boots[i].First.Before(boots[j].First)
+	})
+
+	for i := range boots {
+		boots[i].Offset = i - (len(boots) - 1)
+	}
+
+	return boots, nil
+}
+
+// bootRange finds the first and last entry timestamps of a boot
+func (lr *LogReader) bootRange(id string) (time.Time, time.Time, error) {
+	lr.journal.FlushMatches()
+	defer lr.journal.FlushMatches()
+
+	if err := lr.journal.AddMatch(sdjournal.SD_JOURNAL_FIELD_BOOT_ID + "=" + id); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if err := lr.journal.SeekHead(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if n, err := lr.journal.Next(); err != nil || n == 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("no entries for boot %s", id)
+	}
+	first, err := lr.journal.GetRealtimeUsec()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if err := lr.journal.SeekTail(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if n, err := lr.journal.Previous(); err != nil || n == 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("no entries for boot %s", id)
+	}
+	last, err := lr.journal.GetRealtimeUsec()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return usecToTime(first), usecToTime(last), nil
+}
+
+// ResolveBoot finds a boot by spec: "all" (or empty), an offset such as
+// "0", "-1" or "current"/"previous", or a boot ID
+func ResolveBoot(boots []types.Boot, spec string) (*types.Boot, error) {
+	switch spec {
+	case "", "all":
+		return nil, nil
+	case "current":
+		spec = "0"
+	case "previous":
+		spec = "-1"
+	}
+
+	if offset, err := strconv.Atoi(spec); err == nil {
+		for i := range boots {
+			if boots[i].Offset == offset {
+				return &boots[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no boot with offset %d", offset)
+	}
+
+	for i := range boots {
+		if strings.EqualFold(boots[i].ID, spec) {
+			return &boots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown boot %q", spec)
+}
+
+// usecToTime converts journal microseconds since the epoch to a time
+func usecToTime(usec uint64) time.Time {
+	return time.Unix(0, int64(usec)*1000)
+}
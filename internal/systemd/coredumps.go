@@ -0,0 +1,59 @@
+package systemd
+
+import (
+	"fmt"
+	"strconv"
+
+	"sdtop/internal/types"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// ListCoredumps returns up to count crash reports from systemd-coredump,
+// newest first. An empty unit lists crashes of all units.
+func (lr *LogReader) ListCoredumps(unit string, count int) ([]types.Coredump, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.journal.FlushMatches()
+	if err := lr.journal.AddMatch(sdjournal.SD_JOURNAL_FIELD_MESSAGE_ID + "=" + MessageIDCoredump); err != nil {
+		return nil, fmt.Errorf("failed to add match: %w", err)
+	}
+	if unit != "" {
+		if err := lr.journal.AddMatch(fieldCoredumpUnit + "=" + unit); err != nil {
+			return nil, fmt.Errorf("failed to add unit match: %w", err)
+		}
+	}
+
+	if err := lr.journal.SeekTail(); err != nil {
+		return nil, fmt.Errorf("failed to seek tail: %w", err)
+	}
+
+	var dumps []types.Coredump
+	for len(dumps) < count {
+		n, err := lr.journal.Previous()
+		if err != nil || n == 0 {
+			break
+		}
+
+		entry, err := lr.journal.GetEntry()
+		if err != nil {
+			continue
+		}
+
+		pid, _ := strconv.Atoi(entry.Fields["COREDUMP_PID"])
+		signal, _ := strconv.Atoi(entry.Fields["COREDUMP_SIGNAL"])
+		dumps = append(dumps, types.Coredump{
+			Time:    usecToTime(entry.RealtimeTimestamp),
+			Unit:    entry.Fields[fieldCoredumpUnit],
+			PID:     pid,
+			Signal:  signal,
+			Exe:     entry.Fields["COREDUMP_EXE"],
+			Comm:    entry.Fields["COREDUMP_COMM"],
+			Message: entry.Fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE],
+			Cursor:  entry.Cursor,
+		})
+	}
+
+	return dumps, nil
+}
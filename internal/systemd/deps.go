@@ -0,0 +1,68 @@
+package systemd
+
+import (
+	"sort"
+
+	"sdtop/internal/types"
+)
+
+// Dependency properties followed by the dependency view, like
+// systemctl list-dependencies and its --reverse flag
+var (
+	ForwardDependencies = []string{"Requires", "Wants", "BindsTo", "PartOf"}
+	ReverseDependencies = []string{"RequiredBy", "WantedBy"}
+)
+
+// GetDependencies returns the units a unit depends on, or with reverse the
+// units depending on it, with their current state, sorted by name
+func (m *Manager) GetDependencies(unitName string, reverse bool) ([]types.Dependency, error) {
+	props, err := m.GetUnitProperties(unitName)
+	if err != nil {
+		return nil, err
+	}
+
+	relations := ForwardDependencies
+	if reverse {
+		relations = ReverseDependencies
+	}
+
+	// A unit listed under several properties is shown once, under the
+	// strongest one
+	var deps []types.Dependency
+	seen := map[string]bool{}
+	for _, relation := range relations {
+		names, _ := props[relation].([]string)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			deps = append(deps, types.Dependency{Unit: name, Relation: relation})
+		}
+	}
+	if len(deps) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, len(deps))
+	for i, dep := range deps {
+		names[i] = dep.Unit
+	}
+	statuses, err := m.conn.ListUnitsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string][2]string, len(statuses))
+	for _, status := range statuses {
+		states[status.Name] = [2]string{status.ActiveState, status.SubState}
+	}
+	for i := range deps {
+		state := states[deps[i].Unit]
+		deps[i].ActiveState, deps[i].SubState = state[0], state[1]
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Unit < deps[j].Unit
+	})
+	return deps, nil
+}
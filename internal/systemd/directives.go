@@ -0,0 +1,185 @@
+package systemd
+
+import "strings"
+
+// Directives known to the linter, by section, from systemd.unit(5),
+// systemd.service(5), systemd.exec(5), systemd.kill(5),
+// systemd.resource-control(5) and the other unit type man pages
+
+const unitDirectives = `Description Documentation Wants Requires Requisite BindsTo PartOf
+Upholds Conflicts Before After OnFailure OnSuccess PropagatesReloadTo ReloadPropagatedFrom
+PropagatesStopTo StopPropagatedFrom JoinsNamespaceOf RequiresMountsFor OnFailureJobMode
+IgnoreOnIsolate StopWhenUnneeded RefuseManualStart RefuseManualStop AllowIsolate
+DefaultDependencies SurviveFinalKillSignal CollectMode FailureAction SuccessAction
+FailureActionExitStatus SuccessActionExitStatus JobTimeoutSec JobRunningTimeoutSec
+JobTimeoutAction JobTimeoutRebootArgument StartLimitIntervalSec StartLimitBurst
+StartLimitAction RebootArgument SourcePath`
+
+// conditions are valid both as Condition* and Assert* directives
+const conditions = `Architecture Firmware Virtualization Host KernelCommandLine
+KernelVersion Credential Environment Security Capability ACPower NeedsUpdate FirstBoot
+PathExists PathExistsGlob PathIsDirectory PathIsSymbolicLink PathIsMountPoint
+PathIsReadWrite PathIsEncrypted DirectoryNotEmpty FileNotEmpty FileIsExecutable User
+Group ControlGroupController Memory CPUs CPUFeature OSRelease MemoryPressure CPUPressure
+IOPressure`
+
+const installDirectives = `Alias WantedBy RequiredBy UpheldBy Also DefaultInstance`
+
+const serviceDirectives = `Type ExitType RemainAfterExit GuessMainPID PIDFile BusName
+ExecStart ExecStartPre ExecStartPost ExecCondition ExecReload ExecStop ExecStopPost
+RestartSec RestartSteps RestartMaxDelaySec TimeoutStartSec TimeoutStopSec
+TimeoutAbortSec TimeoutSec TimeoutStartFailureMode TimeoutStopFailureMode RuntimeMaxSec
+RuntimeRandomizedExtraSec WatchdogSec Restart RestartMode SuccessExitStatus
+RestartPreventExitStatus RestartForceExitStatus RootDirectoryStartOnly NonBlocking
+NotifyAccess Sockets FileDescriptorStoreMax FileDescriptorStorePreserve
+USBFunctionDescriptors USBFunctionStrings OOMPolicy OpenFile ReloadSignal`
+
+const execDirectives = `ExecSearchPath WorkingDirectory RootDirectory RootImage
+RootImageOptions RootEphemeral RootHash RootHashSignature RootVerity MountAPIVFS
+ProtectProc ProcSubset BindPaths BindReadOnlyPaths MountImages ExtensionImages
+ExtensionDirectories User Group DynamicUser SupplementaryGroups PAMName
+CapabilityBoundingSet AmbientCapabilities NoNewPrivileges SecureBits SELinuxContext
+AppArmorProfile SmackProcessLabel LimitCPU LimitFSIZE LimitDATA LimitSTACK LimitCORE
+LimitRSS LimitNOFILE LimitAS LimitNPROC LimitMEMLOCK LimitLOCKS LimitSIGPENDING
+LimitMSGQUEUE LimitNICE LimitRTPRIO LimitRTTIME UMask CoredumpFilter KeyringMode
+OOMScoreAdjust TimerSlackNSec MemoryKSM Personality IgnoreSIGPIPE Nice
+CPUSchedulingPolicy CPUSchedulingPriority CPUSchedulingResetOnFork CPUAffinity NUMAPolicy
+NUMAMask IOSchedulingClass IOSchedulingPriority ProtectSystem ProtectHome
+RuntimeDirectory StateDirectory CacheDirectory LogsDirectory ConfigurationDirectory
+RuntimeDirectoryMode StateDirectoryMode CacheDirectoryMode LogsDirectoryMode
+ConfigurationDirectoryMode RuntimeDirectoryPreserve TimeoutCleanSec ReadWritePaths
+ReadOnlyPaths InaccessiblePaths ExecPaths NoExecPaths TemporaryFileSystem PrivateTmp
+PrivateDevices PrivateNetwork NetworkNamespacePath PrivateIPC IPCNamespacePath
+PrivateUsers ProtectHostname ProtectClock ProtectKernelTunables ProtectKernelModules
+ProtectKernelLogs ProtectControlGroups RestrictAddressFamilies RestrictFileSystems
+RestrictNamespaces LockPersonality MemoryDenyWriteExecute RestrictRealtime
+RestrictSUIDSGID RemoveIPC PrivateMounts MountFlags SystemCallFilter
+SystemCallErrorNumber SystemCallArchitectures SystemCallLog Environment EnvironmentFile
+PassEnvironment UnsetEnvironment StandardInput StandardOutput StandardError
+StandardInputText StandardInputData LogLevelMax LogExtraFields LogRateLimitIntervalSec
+LogRateLimitBurst LogFilterPatterns LogNamespace SyslogIdentifier SyslogFacility
+SyslogLevel SyslogLevelPrefix TTYPath TTYReset TTYVHangup TTYRows TTYColumns
+TTYVTDisallocate LoadCredential LoadCredentialEncrypted ImportCredential SetCredential
+SetCredentialEncrypted UtmpIdentifier UtmpMode SetLoginEnvironment KillMode KillSignal
+RestartKillSignal SendSIGHUP SendSIGKILL FinalKillSignal WatchdogSignal`
+
+const resourceDirectives = `CPUAccounting CPUWeight StartupCPUWeight CPUQuota
+CPUQuotaPeriodSec AllowedCPUs StartupAllowedCPUs AllowedMemoryNodes
+StartupAllowedMemoryNodes MemoryAccounting MemoryMin MemoryLow StartupMemoryLow
+DefaultStartupMemoryLow MemoryHigh StartupMemoryHigh MemoryMax StartupMemoryMax
+MemorySwapMax StartupMemorySwapMax MemoryZSwapMax StartupMemoryZSwapMax TasksAccounting
+TasksMax IOAccounting IOWeight StartupIOWeight IODeviceWeight IOReadBandwidthMax
+IOWriteBandwidthMax IOReadIOPSMax IOWriteIOPSMax IODeviceLatencyTargetSec IPAccounting
+IPAddressAllow IPAddressDeny IPIngressFilterPath IPEgressFilterPath BPFProgram
+SocketBindAllow SocketBindDeny RestrictNetworkInterfaces NFTSet DeviceAllow DevicePolicy
+Slice Delegate DelegateSubgroup DisableControllers ManagedOOMSwap
+ManagedOOMMemoryPressure ManagedOOMMemoryPressureLimit ManagedOOMPreference
+MemoryPressureWatch MemoryPressureThresholdSec`
+
+const socketDirectives = `ListenStream ListenDatagram ListenSequentialPacket ListenFIFO
+ListenSpecial ListenNetlink ListenMessageQueue ListenUSBFunction SocketProtocol
+BindIPv6Only Backlog BindToDevice SocketUser SocketGroup SocketMode DirectoryMode Accept
+Writable FlushPending MaxConnections MaxConnectionsPerSource KeepAlive KeepAliveTimeSec
+KeepAliveIntervalSec KeepAliveProbes NoDelay Priority DeferAcceptSec ReceiveBuffer
+SendBuffer IPTOS IPTTL Mark ReusePort SmackLabel SmackLabelIPIn SmackLabelIPOut
+SELinuxContextFromNet PipeSize MessageQueueMaxMessages MessageQueueMessageSize FreeBind
+Transparent Broadcast PassCredentials PassSecurity PassPacketInfo Timestamping
+TCPCongestion ExecStartPre ExecStartPost ExecStopPre ExecStopPost TimeoutSec Service
+RemoveOnStop Symlinks FileDescriptorName TriggerLimitIntervalSec TriggerLimitBurst
+PollLimitIntervalSec PollLimitBurst`
+
+const timerDirectives = `OnActiveSec OnBootSec OnStartupSec OnUnitActiveSec
+OnUnitInactiveSec OnCalendar AccuracySec RandomizedDelaySec FixedRandomDelay
+OnClockChange OnTimezoneChange Unit Persistent WakeSystem RemainAfterElapse`
+
+const pathDirectives = `PathExists PathExistsGlob PathChanged PathModified
+DirectoryNotEmpty Unit MakeDirectory DirectoryMode TriggerLimitIntervalSec
+TriggerLimitBurst`
+
+const mountDirectives = `What Where Type Options SloppyOptions LazyUnmount
+ReadWriteOnly ForceUnmount DirectoryMode TimeoutSec`
+
+const automountDirectives = `Where ExtraOptions DirectoryMode TimeoutIdleSec`
+
+const swapDirectives = `What Priority Options TimeoutSec`
+
+const scopeDirectives = `RuntimeMaxSec RuntimeRandomizedExtraSec OOMPolicy`
+
+// deprecatedDirectives still work but have a replacement
+var deprecatedDirectives = map[string]string{
+	"CPUShares":             "CPUWeight=",
+	"StartupCPUShares":      "StartupCPUWeight=",
+	"MemoryLimit":           "MemoryMax=",
+	"BlockIOAccounting":     "IOAccounting=",
+	"BlockIOWeight":         "IOWeight=",
+	"StartupBlockIOWeight":  "StartupIOWeight=",
+	"BlockIODeviceWeight":   "IODeviceWeight=",
+	"BlockIOReadBandwidth":  "IOReadBandwidthMax=",
+	"BlockIOWriteBandwidth": "IOWriteBandwidthMax=",
+	"PermissionsStartOnly":  "the + prefix on Exec lines",
+	"StartLimitInterval":    "StartLimitIntervalSec= in [Unit]",
+}
+
+// sectionDirectives maps each section to its known directives
+var sectionDirectives = map[string]map[string]bool{
+	"Unit":      directiveSet(unitDirectives, prefixed("Condition", conditions), prefixed("Assert", conditions)),
+	"Install":   directiveSet(installDirectives),
+	"Service":   directiveSet(serviceDirectives, execDirectives, resourceDirectives),
+	"Socket":    directiveSet(socketDirectives, execDirectives, resourceDirectives),
+	"Mount":     directiveSet(mountDirectives, execDirectives, resourceDirectives),
+	"Swap":      directiveSet(swapDirectives, execDirectives, resourceDirectives),
+	"Timer":     directiveSet(timerDirectives),
+	"Path":      directiveSet(pathDirectives),
+	"Automount": directiveSet(automountDirectives),
+	"Slice":     directiveSet(resourceDirectives),
+	"Scope":     directiveSet(scopeDirectives, resourceDirectives),
+}
+
+// directiveSet builds a set from whitespace-separated directive lists
+func directiveSet(lists ...string) map[string]bool {
+	set := map[string]bool{}
+	for _, list := range lists {
+		for _, name := range strings.Fields(list) {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// prefixed prepends a prefix to every directive in a list
+func prefixed(prefix, list string) string {
+	names := strings.Fields(list)
+	for i, name := range names {
+		names[i] = prefix + name
+	}
+	return strings.Join(names, " ")
+}
+
+// listDirectives accumulate when set more than once, so setting them in
+// several files isn't a conflict
+var listDirectives = directiveSet(`Documentation Wants Requires Requisite BindsTo PartOf
+Upholds Conflicts Before After OnFailure OnSuccess PropagatesReloadTo
+ReloadPropagatedFrom PropagatesStopTo StopPropagatedFrom JoinsNamespaceOf
+RequiresMountsFor Alias WantedBy RequiredBy UpheldBy Also Environment EnvironmentFile
+PassEnvironment UnsetEnvironment ReadWritePaths ReadOnlyPaths InaccessiblePaths ExecPaths
+NoExecPaths BindPaths BindReadOnlyPaths TemporaryFileSystem SupplementaryGroups
+CapabilityBoundingSet AmbientCapabilities SystemCallFilter RestrictAddressFamilies
+RestrictFileSystems RestrictNamespaces DeviceAllow IPAddressAllow IPAddressDeny
+LoadCredential LoadCredentialEncrypted ImportCredential SetCredential
+SetCredentialEncrypted SuccessExitStatus RestartPreventExitStatus RestartForceExitStatus
+RuntimeDirectory StateDirectory CacheDirectory LogsDirectory ConfigurationDirectory
+Sockets Symlinks LogExtraFields LogFilterPatterns SocketBindAllow SocketBindDeny
+IODeviceWeight IOReadBandwidthMax IOWriteBandwidthMax IOReadIOPSMax IOWriteIOPSMax
+OnActiveSec OnBootSec OnStartupSec OnUnitActiveSec OnUnitInactiveSec OnCalendar
+PathExists PathExistsGlob PathChanged PathModified DirectoryNotEmpty OpenFile NFTSet`)
+
+// commandLineDirectives are the settings holding a command line to run
+var commandLineDirectives = directiveSet(`ExecStart ExecStartPre ExecStartPost
+ExecCondition ExecReload ExecStop ExecStopPre ExecStopPost`)
+
+// isListDirective reports whether a directive accumulates values
+func isListDirective(name string) bool {
+	return listDirectives[name] || strings.HasPrefix(name, "Exec") ||
+		strings.HasPrefix(name, "Listen") || strings.HasPrefix(name, "Condition") ||
+		strings.HasPrefix(name, "Assert")
+}
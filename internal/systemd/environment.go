@@ -0,0 +1,190 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// commandDirectives are the command lines shown for a service, in the order
+// systemd runs them
+var commandDirectives = []string{"ExecStartPre", "ExecStart", "ExecStartPost", "ExecReload", "ExecStop", "ExecStopPost"}
+
+// GetServiceEnvironment reads a service's commands and configured
+// environment, and the environment its main process was started with
+func (m *Manager) GetServiceEnvironment(unitName string) (*types.ServiceEnvironment, error) {
+	props, err := m.GetUnitProperties(unitName)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &types.ServiceEnvironment{Unit: unitName}
+	env.User, _ = props["User"].(string)
+	env.Group, _ = props["Group"].(string)
+	env.WorkingDirectory, _ = props["WorkingDirectory"].(string)
+	env.MainPID, _ = props["MainPID"].(uint32)
+	env.Environment, _ = props["Environment"].([]string)
+
+	for _, directive := range commandDirectives {
+		env.Commands = append(env.Commands, execCommands(props, directive)...)
+	}
+
+	// a(sb): path and whether it's optional
+	files, _ := props["EnvironmentFiles"].([][]interface{})
+	for _, f := range files {
+		if len(f) != 2 {
+			continue
+		}
+		path, _ := f[0].(string)
+		optional, _ := f[1].(bool)
+		env.Files = append(env.Files, readEnvironmentFiles(path, optional)...)
+	}
+
+	var runtime []string
+	if env.MainPID == 0 {
+		env.RuntimeErr = "the service has no main process"
+	} else if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", env.MainPID)); err != nil {
+		env.RuntimeErr = err.Error()
+	} else {
+		runtime = strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	}
+	env.Vars = CompareEnvironment(env.Environment, env.Files, runtime, env.RuntimeErr == "")
+
+	return env, nil
+}
+
+// execCommands reads an a(sasbttttuii) command property: path, argv,
+// ignore failure, start and exit timestamps, PID, exit code and status
+func execCommands(props map[string]interface{}, directive string) []types.ExecCommand {
+	entries, _ := props[directive].([][]interface{})
+
+	var commands []types.ExecCommand
+	for _, e := range entries {
+		if len(e) != 10 {
+			continue
+		}
+		cmd := types.ExecCommand{Directive: directive}
+		cmd.Path, _ = e[0].(string)
+		cmd.Argv, _ = e[1].([]string)
+		cmd.IgnoreFailure, _ = e[2].(bool)
+		if usec, ok := e[3].(uint64); ok && usec > 0 {
+			cmd.StartedAt = usecToTime(usec)
+		}
+		if usec, ok := e[5].(uint64); ok && usec > 0 {
+			cmd.ExitedAt = usecToTime(usec)
+		}
+		cmd.PID, _ = e[7].(uint32)
+		cmd.ExitCode, _ = e[8].(int32)
+		cmd.ExitStatus, _ = e[9].(int32)
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// readEnvironmentFiles reads an EnvironmentFile= setting, which may be a
+// glob matching several files
+func readEnvironmentFiles(pattern string, optional bool) []types.EnvironmentFile {
+	paths, _ := filepath.Glob(pattern)
+	if len(paths) == 0 {
+		paths = []string{pattern}
+	}
+
+	files := make([]types.EnvironmentFile, 0, len(paths))
+	for _, path := range paths {
+		f := types.EnvironmentFile{Path: path, Optional: optional}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Err = err.Error()
+		} else {
+			f.Vars = ParseEnvironmentFile(data)
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// ParseEnvironmentFile reads KEY=VALUE lines the way systemd does for
+// EnvironmentFile=: comments start with # or ;, values may be quoted, and
+// a trailing backslash continues a line
+func ParseEnvironmentFile(data []byte) []string {
+	var vars []string
+	var pending string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := pending + scanner.Text()
+		pending = ""
+		if strings.HasSuffix(line, `\`) {
+			pending = strings.TrimSuffix(line, `\`)
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		if !ok || key == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars = append(vars, key+"="+value)
+	}
+	return vars
+}
+
+// CompareEnvironment lines up the configured variables with the runtime
+// ones. Files override Environment=, and later settings override earlier
+// ones, as in systemd. Configured variables come first, in the order they
+// were set, then the ones only present at runtime, sorted.
+func CompareEnvironment(environment []string, files []types.EnvironmentFile, runtime []string, haveRuntime bool) []types.EnvVar {
+	var vars []types.EnvVar
+	index := map[string]int{}
+
+	set := func(assignment, source string) {
+		key, value, _ := strings.Cut(assignment, "=")
+		if i, ok := index[key]; ok {
+			vars[i].Configured, vars[i].Source = value, source
+			return
+		}
+		index[key] = len(vars)
+		vars = append(vars, types.EnvVar{Key: key, Configured: value, Source: source, InConfig: true})
+	}
+	for _, assignment := range environment {
+		set(assignment, "Environment=")
+	}
+	for _, f := range files {
+		for _, assignment := range f.Vars {
+			set(assignment, f.Path)
+		}
+	}
+
+	if !haveRuntime {
+		return vars
+	}
+
+	configured := len(vars)
+	for _, assignment := range runtime {
+		key, value, _ := strings.Cut(assignment, "=")
+		if i, ok := index[key]; ok {
+			vars[i].Runtime, vars[i].InRuntime = value, true
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, types.EnvVar{Key: key, Runtime: value, InRuntime: true})
+	}
+
+	extra := vars[configured:]
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Key < extra[j].Key })
+	return vars
+}
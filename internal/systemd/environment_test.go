@@ -0,0 +1,61 @@
+package systemd
+
+import (
+	"reflect"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+func TestParseEnvironmentFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"plain", "A=1\nB=two words\n", []string{"A=1", "B=two words"}},
+		{"comments and blanks", "# comment\n; also a comment\n\n  \nA=1\n", []string{"A=1"}},
+		{"quotes", `A="quoted value"` + "\nB='single'\nC=\"unbalanced\n", []string{"A=quoted value", "B=single", `C="unbalanced`}},
+		{"spaces and export", "  export A = 1  \nB=\n", []string{"A=1", "B="}},
+		{"continuation", "A=first \\\nsecond\nB=2\n", []string{"A=first second", "B=2"}},
+		{"not assignments", "no equals sign\n=value\nA=1\n", []string{"A=1"}},
+		{"value with equals", "URL=http://example.com/?a=b\n", []string{"URL=http://example.com/?a=b"}},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseEnvironmentFile([]byte(tt.data)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompareEnvironment(t *testing.T) {
+	environment := []string{"A=1", "B=2", "A=3"}
+	files := []types.EnvironmentFile{
+		{Path: "/etc/default/example", Vars: []string{"B=file", "C=4"}},
+		{Path: "/etc/default/missing", Optional: true, Err: "no such file"},
+	}
+	runtime := []string{"PATH=/usr/bin", "C=4", "A=3", "B=changed", "INVOCATION_ID=abc"}
+
+	want := []types.EnvVar{
+		{Key: "A", Configured: "3", Source: "Environment=", Runtime: "3", InConfig: true, InRuntime: true},
+		{Key: "B", Configured: "file", Source: "/etc/default/example", Runtime: "changed", InConfig: true, InRuntime: true},
+		{Key: "C", Configured: "4", Source: "/etc/default/example", Runtime: "4", InConfig: true, InRuntime: true},
+		{Key: "INVOCATION_ID", Runtime: "abc", InRuntime: true},
+		{Key: "PATH", Runtime: "/usr/bin", InRuntime: true},
+	}
+	if got := CompareEnvironment(environment, files, runtime, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Without a runtime environment only the configuration is listed
+	want = []types.EnvVar{
+		{Key: "A", Configured: "3", Source: "Environment=", InConfig: true},
+		{Key: "B", Configured: "file", Source: "/etc/default/example", InConfig: true},
+		{Key: "C", Configured: "4", Source: "/etc/default/example", InConfig: true},
+	}
+	if got := CompareEnvironment(environment, files, nil, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("without runtime: got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package systemd
+
+import (
+	"fmt"
+	"sort"
+	"syscall"
+
+	"sdtop/internal/types"
+
+	"golang.org/x/sys/unix"
+)
+
+// How a process ended, as reported in ExecMainCode (see waitid(2))
+const (
+	CLDExited = 1
+	CLDKilled = 2
+	CLDDumped = 3
+)
+
+// ListFailedUnits returns every unit in the failed state with the details
+// needed to triage it, most recent failure first
+func (m *Manager) ListFailedUnits() ([]types.FailedUnit, error) {
+	units, err := m.conn.ListUnitsFiltered([]string{"failed"})
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make([]types.FailedUnit, 0, len(units))
+	for _, unit := range units {
+		f := types.FailedUnit{
+			Name:        unit.Name,
+			Description: unit.Description,
+		}
+
+		// Missing properties (e.g. ExecMainCode on a mount) stay zero
+		if props, err := m.GetUnitProperties(unit.Name); err == nil {
+			f.Result, _ = props["Result"].(string)
+			f.ExitCode, _ = props["ExecMainCode"].(int32)
+			f.ExitStatus, _ = props["ExecMainStatus"].(int32)
+			f.NRestarts, _ = props["NRestarts"].(uint32)
+			if usec, ok := props["StateChangeTimestamp"].(uint64); ok && usec > 0 {
+				f.FailedAt = usecToTime(usec)
+			}
+		}
+
+		failed = append(failed, f)
+	}
+
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].FailedAt.After(failed[j].FailedAt)
+	})
+
+	return failed, nil
+}
+
+// ResetFailedUnit clears the failed state of a unit (systemctl reset-failed)
+func (m *Manager) ResetFailedUnit(unitName string) error {
+	return m.conn.ResetFailedUnit(unitName)
+}
+
+// DescribeExit explains how a main process ended, like systemctl status
+// ("exited, status=1" or "killed, signal=SEGV")
+func DescribeExit(code, status int32) string {
+	switch code {
+	case CLDExited:
+		return fmt.Sprintf("exited, status=%d", status)
+	case CLDKilled:
+		return "killed, signal=" + SignalName(status)
+	case CLDDumped:
+		return "dumped core, signal=" + SignalName(status)
+	default:
+		return ""
+	}
+}
+
+// SignalName returns the short name of a signal number ("SEGV")
+func SignalName(sig int32) string {
+	if name := unix.SignalName(syscall.Signal(sig)); name != "" {
+		return name[3:]
+	}
+	return fmt.Sprint(sig)
+}
@@ -0,0 +1,82 @@
+package systemd
+
+import (
+	"sync"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// FlapDetector flags services that restart too often or keep oscillating
+// between active and failed
+type FlapDetector struct {
+	// MaxStarts is how many starts within Window are still considered
+	// normal
+	MaxStarts int
+	Window    time.Duration
+
+	mu      sync.Mutex
+	history map[string][]types.StateChange
+}
+
+// NewFlapDetector creates a detector flagging more than maxStarts starts
+// within window
+func NewFlapDetector(maxStarts int, window time.Duration) *FlapDetector {
+	return &FlapDetector{
+		MaxStarts: maxStarts,
+		Window:    window,
+		history:   map[string][]types.StateChange{},
+	}
+}
+
+// Record adds a state change to the unit's history, dropping changes that
+// fell out of the window. Changes must be transitions, as reported by
+// WatchServiceStates, for Counts to count starts.
+func (d *FlapDetector) Record(change types.StateChange) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	history := append(d.history[change.Unit], change)
+	cutoff := change.Time.Add(-d.Window)
+	for len(history) > 0 && history[0].Time.Before(cutoff) {
+		history = history[1:]
+	}
+	d.history[change.Unit] = history
+}
+
+// Flapping reports whether a unit restarted more than MaxStarts times in
+// the window, or went from active to failed and back more than once
+func (d *FlapDetector) Flapping(unit string) bool {
+	starts, failures := d.Counts(unit)
+	return starts > d.MaxStarts || (failures >= 2 && starts >= 2)
+}
+
+// Counts returns how many times a unit became active and failed within
+// the window
+func (d *FlapDetector) Counts(unit string) (starts, failures int) {
+	for _, change := range d.Timeline(unit) {
+		switch change.ActiveState {
+		case "active":
+			starts++
+		case "failed":
+			failures++
+		}
+	}
+	return starts, failures
+}
+
+// Timeline returns the unit's state changes within the window, oldest
+// first
+func (d *FlapDetector) Timeline(unit string) []types.StateChange {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cutoff := time.Now().Add(-d.Window)
+	var timeline []types.StateChange
+	for _, change := range d.history[unit] {
+		if !change.Time.Before(cutoff) {
+			timeline = append(timeline, change)
+		}
+	}
+	return timeline
+}
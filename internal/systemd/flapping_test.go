@@ -0,0 +1,71 @@
+package systemd
+
+import (
+	"testing"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// stateAt is a state a unit entered some minutes ago
+type stateAt struct {
+	state string
+	ago   int
+}
+
+// recordStates records a unit's state changes, oldest first
+func recordStates(d *FlapDetector, unit string, changes []stateAt) {
+	now := time.Now()
+	for _, c := range changes {
+		d.Record(types.StateChange{
+			Unit:        unit,
+			ActiveState: c.state,
+			Time:        now.Add(-time.Duration(c.ago) * time.Minute),
+		})
+	}
+}
+
+func TestFlapDetectorWindow(t *testing.T) {
+	d := NewFlapDetector(2, 5*time.Minute)
+	recordStates(d, "app.service", []stateAt{
+		{"active", 20}, {"failed", 19}, {"active", 10}, {"active", 4}, {"failed", 3}, {"active", 2},
+	})
+
+	// Changes older than the window are dropped as newer ones are recorded
+	if got := len(d.history["app.service"]); got != 3 {
+		t.Errorf("%d changes kept, want 3", got)
+	}
+	if starts, failures := d.Counts("app.service"); starts != 2 || failures != 1 {
+		t.Errorf("Counts = %d starts, %d failures, want 2 and 1", starts, failures)
+	}
+
+	// Without new changes, the window still moves on with the clock
+	recordStates(d, "old.service", []stateAt{{"active", 7}, {"active", 6}})
+	if got := d.Timeline("old.service"); len(got) != 0 {
+		t.Errorf("Timeline of expired changes = %v, want none", got)
+	}
+}
+
+func TestFlapDetectorFlapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []stateAt
+		want    bool
+	}{
+		{"single start", []stateAt{{"active", 1}}, false},
+		{"starts up to the limit", []stateAt{{"active", 3}, {"active", 2}, {"active", 1}}, false},
+		{"starts past the limit", []stateAt{{"active", 4}, {"active", 3}, {"active", 2}, {"active", 1}}, true},
+		{"failed once", []stateAt{{"active", 3}, {"failed", 2}, {"active", 1}}, false},
+		{"oscillating", []stateAt{{"active", 4}, {"failed", 3}, {"active", 2}, {"failed", 1}}, true},
+		{"oscillating before the window", []stateAt{{"active", 40}, {"failed", 30}, {"active", 20}, {"failed", 10}}, false},
+	}
+
+	for _, tt := range tests {
+		d := NewFlapDetector(3, 5*time.Minute)
+		recordStates(d, "app.service", tt.changes)
+		if got := d.Flapping("app.service"); got != tt.want {
+			starts, failures := d.Counts("app.service")
+			t.Errorf("%s: Flapping = %v, want %v (%d starts, %d failures)", tt.name, got, tt.want, starts, failures)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package systemd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// GraphRelations are the properties a unit graph can follow
+var GraphRelations = []string{"Requires", "Wants", "After", "Before", "Conflicts"}
+
+// lowLevelTargets are the early-boot and shutdown targets left out of
+// graphs along with other low-level units
+var lowLevelTargets = map[string]bool{
+	"sysinit.target":      true,
+	"basic.target":        true,
+	"local-fs.target":     true,
+	"local-fs-pre.target": true,
+	"swap.target":         true,
+	"slices.target":       true,
+	"sockets.target":      true,
+	"paths.target":        true,
+	"timers.target":       true,
+	"shutdown.target":     true,
+	"umount.target":       true,
+	"final.target":        true,
+}
+
+// GraphOptions controls which units and edges a unit graph includes
+type GraphOptions struct {
+	Depth         int      // Edges followed away from the root, 0 for no limit
+	Relations     []string // Properties to follow, all GraphRelations when empty
+	ExcludeSystem bool     // Leave out low-level units (see IsLowLevelUnit)
+}
+
+// ParseRelations parses a comma-separated list of graph relations in any
+// case, e.g. "requires,after"
+func ParseRelations(s string) ([]string, error) {
+	var relations []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, relation := range GraphRelations {
+			if strings.EqualFold(name, relation) {
+				relations = append(relations, relation)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown edge type %q (want %s)", name, strings.Join(GraphRelations, ", "))
+		}
+	}
+	return relations, nil
+}
+
+// IsLowLevelUnit reports whether a unit is plumbing most graphs are
+// clearer without: devices, mounts, swaps, slices, scopes, automounts,
+// early-boot targets and systemd's own services
+func IsLowLevelUnit(name string) bool {
+	if lowLevelTargets[name] || strings.HasPrefix(name, "systemd-") {
+		return true
+	}
+	switch name[strings.LastIndexByte(name, '.')+1:] {
+	case "device", "mount", "swap", "slice", "scope", "automount":
+		return true
+	}
+	return false
+}
+
+// GetUnitGraph builds the graph of units reachable from root through the
+// selected relations, or of all loaded units when root is empty. Before
+// edges are stored as the After edge they imply when both are followed, so
+// each ordering appears once.
+func (m *Manager) GetUnitGraph(root string, opts GraphOptions) (*types.UnitGraph, error) {
+	relations := opts.Relations
+	if len(relations) == 0 {
+		relations = GraphRelations
+	}
+	followsAfter := false
+	for _, relation := range relations {
+		followsAfter = followsAfter || relation == "After"
+	}
+
+	var queue []string
+	if root != "" {
+		queue = []string{root}
+	} else {
+		units, err := m.conn.ListUnits()
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range units {
+			queue = append(queue, unit.Name)
+		}
+	}
+
+	graph := &types.UnitGraph{Root: root, States: map[string]string{}}
+	depth := map[string]int{}
+	included := func(name string) bool {
+		return name == root || !opts.ExcludeSystem || !IsLowLevelUnit(name)
+	}
+	for _, name := range queue {
+		if included(name) {
+			depth[name] = 0
+		}
+	}
+
+	seen := map[types.UnitEdge]bool{}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := depth[name]; !ok || (opts.Depth > 0 && depth[name] >= opts.Depth) {
+			continue
+		}
+
+		props, err := m.GetUnitProperties(name)
+		if err != nil {
+			if name == root {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, relation := range relations {
+			others, _ := props[relation].([]string)
+			for _, other := range others {
+				if !included(other) {
+					continue
+				}
+
+				edge := types.UnitEdge{From: name, To: other, Relation: relation}
+				if relation == "Before" && followsAfter {
+					edge = types.UnitEdge{From: other, To: name, Relation: "After"}
+				}
+				if !seen[edge] {
+					seen[edge] = true
+					graph.Edges = append(graph.Edges, edge)
+				}
+
+				if _, ok := depth[other]; !ok {
+					depth[other] = depth[name] + 1
+					queue = append(queue, other)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(depth))
+	for name := range depth {
+		names = append(names, name)
+	}
+	statuses, err := m.conn.ListUnitsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		graph.States[status.Name] = status.ActiveState
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Relation < b.Relation
+	})
+	return graph, nil
+}
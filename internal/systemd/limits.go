@@ -0,0 +1,214 @@
+package systemd
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+	"golang.org/x/sys/unix"
+)
+
+// ResourceLimit is a cgroup resource control that can be changed on a
+// running unit
+type ResourceLimit struct {
+	Name     string // Directive, e.g. CPUQuota
+	Property string // D-Bus property holding the value
+	Help     string
+}
+
+// ResourceLimits are the controls offered by the limits form
+var ResourceLimits = []ResourceLimit{
+	{"CPUQuota", "CPUQuotaPerSecUSec", "share of one CPU, e.g. 50% or 200%; infinity for none"},
+	{"MemoryMax", "MemoryMax", "hard memory limit, e.g. 512M, 2G or 25%; infinity for none"},
+	{"MemoryHigh", "MemoryHigh", "memory throttling threshold, same format as MemoryMax"},
+	{"TasksMax", "TasksMax", "maximum number of tasks; infinity for none"},
+	{"IOWeight", "IOWeight", "1-10000, 100 when unset; empty to unset"},
+	{"CPUWeight", "CPUWeight", "1-10000, 100 when unset; empty to unset"},
+}
+
+// unsetLimit is how systemd reports "infinity" and unset weights
+const unsetLimit = math.MaxUint64
+
+// byteUnits are the suffixes accepted for memory sizes (base 1024)
+var byteUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	{"T", 1 << 40},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+}
+
+// ParseLimit validates a value typed for a resource limit and converts it
+// to the D-Bus property value
+func ParseLimit(name, value string) (uint64, error) {
+	value = strings.TrimSpace(value)
+
+	switch name {
+	case "CPUQuota":
+		if value == "" || value == "infinity" {
+			return unsetLimit, nil
+		}
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || !strings.HasSuffix(value, "%") {
+			return 0, fmt.Errorf("CPUQuota must be a percentage like 50%%")
+		}
+		if maxPct := float64(100 * runtime.NumCPU()); pct < 1 || pct > maxPct {
+			return 0, fmt.Errorf("CPUQuota must be between 1%% and %.0f%% (%d CPUs)", maxPct, runtime.NumCPU())
+		}
+		// The quota is CPU time per second of wall time: 1% is 10ms
+		return uint64(pct * 10000), nil
+
+	case "MemoryMax", "MemoryHigh":
+		if value == "" || value == "infinity" {
+			return unsetLimit, nil
+		}
+		return parseBytes(name, value)
+
+	case "TasksMax":
+		if value == "" || value == "infinity" {
+			return unsetLimit, nil
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("TasksMax must be a positive number or infinity")
+		}
+		return n, nil
+
+	case "IOWeight", "CPUWeight":
+		if value == "" {
+			return unsetLimit, nil
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || n < 1 || n > 10000 {
+			return 0, fmt.Errorf("%s must be between 1 and 10000", name)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("unknown resource limit %s", name)
+}
+
+// parseBytes parses a memory size with an optional K/M/G/T suffix, or a
+// percentage of physical memory
+func parseBytes(name, value string) (uint64, error) {
+	if strings.HasSuffix(value, "%") {
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || pct <= 0 || pct > 100 {
+			return 0, fmt.Errorf("%s percentage must be between 0%% and 100%%", name)
+		}
+		var info unix.Sysinfo_t
+		if err := unix.Sysinfo(&info); err != nil {
+			return 0, err
+		}
+		return uint64(float64(info.Totalram) * float64(info.Unit) * pct / 100), nil
+	}
+
+	multiplier := uint64(1)
+	number := strings.TrimSuffix(strings.ToUpper(value), "B")
+	for _, u := range byteUnits {
+		if strings.HasSuffix(number, u.suffix) {
+			number, multiplier = strings.TrimSuffix(number, u.suffix), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a size like 512M or 2G, a percentage, or infinity", name)
+	}
+	return uint64(n * float64(multiplier)), nil
+}
+
+// FormatLimit formats a resource limit property the way ParseLimit
+// accepts it
+func FormatLimit(name string, v uint64) string {
+	switch name {
+	case "CPUQuota":
+		if v == unsetLimit {
+			return "infinity"
+		}
+		return strconv.FormatFloat(float64(v)/10000, 'f', -1, 64) + "%"
+	case "MemoryMax", "MemoryHigh", "TasksMax":
+		if v == unsetLimit {
+			return "infinity"
+		}
+		if name == "TasksMax" {
+			return strconv.FormatUint(v, 10)
+		}
+		return FormatBytes(v)
+	default:
+		if v == unsetLimit {
+			return ""
+		}
+		return strconv.FormatUint(v, 10)
+	}
+}
+
+// FormatBytes formats a size with the largest K/M/G/T suffix that keeps
+// it readable, e.g. 512M or 1.5G
+func FormatBytes(v uint64) string {
+	for _, u := range byteUnits {
+		if v >= u.size {
+			s := strconv.FormatFloat(float64(v)/float64(u.size), 'f', 1, 64)
+			return strings.TrimSuffix(s, ".0") + u.suffix
+		}
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// CurrentLimits reads the resource limits of a unit, keyed by directive
+func CurrentLimits(props map[string]interface{}) map[string]uint64 {
+	limits := map[string]uint64{}
+	for _, l := range ResourceLimits {
+		if v, ok := props[l.Property].(uint64); ok {
+			limits[l.Name] = v
+		} else {
+			limits[l.Name] = unsetLimit
+		}
+	}
+	return limits
+}
+
+// SetResourceLimits changes resource limits of a unit, keyed by
+// directive. With runtimeOnly the change is lost at the next reboot;
+// otherwise systemd also writes it to a drop-in.
+func (m *Manager) SetResourceLimits(unitName string, runtimeOnly bool, limits map[string]uint64) error {
+	props := limitProperties(limits)
+	if len(props) == 0 {
+		return nil
+	}
+	return m.conn.SetUnitProperties(unitName, runtimeOnly, props...)
+}
+
+// limitProperties converts limits keyed by directive to unit properties
+func limitProperties(limits map[string]uint64) []dbus.Property {
+	var props []dbus.Property
+	for _, l := range ResourceLimits {
+		if v, ok := limits[l.Name]; ok {
+			props = append(props, dbus.Property{Name: l.Property, Value: godbus.MakeVariant(v)})
+		}
+	}
+	return props
+}
+
+// ParseLimitAssignments parses limits written as directives, e.g.
+// MemoryMax=512M CPUQuota=50%
+func ParseLimitAssignments(fields []string) (map[string]uint64, error) {
+	limits := map[string]uint64{}
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a Name=value limit", field)
+		}
+		n, err := ParseLimit(name, value)
+		if err != nil {
+			return nil, err
+		}
+		limits[name] = n
+	}
+	return limits, nil
+}
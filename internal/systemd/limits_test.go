@@ -0,0 +1,146 @@
+package systemd
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name, value string
+		want        uint64
+		wantErr     bool
+	}{
+		{"CPUQuota", "50%", 500000, false},
+		{"CPUQuota", " 100% ", 1000000, false},
+		{"CPUQuota", "infinity", unsetLimit, false},
+		{"CPUQuota", "", unsetLimit, false},
+		{"CPUQuota", "50", 0, true},
+		{"CPUQuota", "0.5%", 0, true},
+		{"MemoryMax", "512M", 512 << 20, false},
+		{"MemoryMax", "2G", 2 << 30, false},
+		{"MemoryMax", "1.5g", 3 << 29, false},
+		{"MemoryMax", "64KB", 64 << 10, false},
+		{"MemoryMax", "4096", 4096, false},
+		{"MemoryHigh", "infinity", unsetLimit, false},
+		{"MemoryMax", "lots", 0, true},
+		{"MemoryMax", "-1M", 0, true},
+		{"MemoryMax", "150%", 0, true},
+		{"TasksMax", "64", 64, false},
+		{"TasksMax", "0", 0, true},
+		{"TasksMax", "infinity", unsetLimit, false},
+		{"IOWeight", "", unsetLimit, false},
+		{"IOWeight", "10000", 10000, false},
+		{"CPUWeight", "10001", 0, true},
+		{"Nice", "5", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.name, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLimit(%q, %q) error = %v, want error %v", tt.name, tt.value, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLimit(%q, %q) = %d, want %d", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseLimitCPUQuotaBound(t *testing.T) {
+	if _, err := ParseLimit("CPUQuota", "100%"); err != nil {
+		t.Errorf("100%% rejected: %v", err)
+	}
+	over := FormatLimit("CPUQuota", uint64(100*runtime.NumCPU()+1)*10000)
+	if _, err := ParseLimit("CPUQuota", over); err == nil {
+		t.Errorf("%s accepted with %d CPUs", over, runtime.NumCPU())
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		v    uint64
+		want string
+	}{
+		{"CPUQuota", 500000, "50%"},
+		{"CPUQuota", 2500000, "250%"},
+		{"CPUQuota", unsetLimit, "infinity"},
+		{"MemoryMax", 512 << 20, "512M"},
+		{"MemoryMax", 3 << 29, "1.5G"},
+		{"MemoryHigh", unsetLimit, "infinity"},
+		{"TasksMax", 4915, "4915"},
+		{"TasksMax", unsetLimit, "infinity"},
+		{"IOWeight", 100, "100"},
+		{"CPUWeight", unsetLimit, ""},
+	}
+
+	for _, tt := range tests {
+		if got := FormatLimit(tt.name, tt.v); got != tt.want {
+			t.Errorf("FormatLimit(%q, %d) = %q, want %q", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLimitRoundTrip(t *testing.T) {
+	for _, l := range ResourceLimits {
+		for _, value := range []string{"", "infinity", "64", "50%", "1G", "1536M"} {
+			// Memory percentages become sizes, which are formatted rounded
+			if strings.HasSuffix(value, "%") && l.Name != "CPUQuota" {
+				continue
+			}
+			v, err := ParseLimit(l.Name, value)
+			if err != nil {
+				continue
+			}
+			back, err := ParseLimit(l.Name, FormatLimit(l.Name, v))
+			if err != nil || back != v {
+				t.Errorf("%s=%s: formatted as %q, parsed back as %d (%v), want %d",
+					l.Name, value, FormatLimit(l.Name, v), back, err, v)
+			}
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "0"},
+		{1023, "1023"},
+		{1024, "1K"},
+		{1536, "1.5K"},
+		{512 << 20, "512M"},
+		{5 << 40, "5T"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.v); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestParseLimitAssignments(t *testing.T) {
+	got, err := ParseLimitAssignments([]string{"MemoryMax=512M", "CPUQuota=50%", "TasksMax=64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{"MemoryMax": 512 << 20, "CPUQuota": 500000, "TasksMax": 64}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("%s = %d, want %d", name, got[name], v)
+		}
+	}
+
+	for _, fields := range [][]string{{"MemoryMax"}, {"MemoryMax=lots"}, {"Nice=5"}} {
+		if _, err := ParseLimitAssignments(fields); err == nil {
+			t.Errorf("ParseLimitAssignments(%q) succeeded, want an error", fields)
+		}
+	}
+}
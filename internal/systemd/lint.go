@@ -0,0 +1,396 @@
+package systemd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// unitSetting is one key=value line of a unit file
+type unitSetting struct {
+	File    string
+	Line    int
+	Section string
+	Key     string
+	Value   string
+}
+
+// lintReporter records a finding
+type lintReporter func(file string, line int, severity types.LintSeverity, format string, args ...interface{})
+
+// unitTypeSections maps unit file suffixes to their type-specific section
+var unitTypeSections = map[string]string{
+	"service":   "Service",
+	"socket":    "Socket",
+	"mount":     "Mount",
+	"automount": "Automount",
+	"swap":      "Swap",
+	"timer":     "Timer",
+	"path":      "Path",
+	"slice":     "Slice",
+	"scope":     "Scope",
+}
+
+// enableableTypes are unit types normally enabled with systemctl enable
+var enableableTypes = map[string]bool{"service": true, "socket": true, "timer": true, "path": true}
+
+// UnitFilePaths returns the unit file and drop-ins systemd loaded for a
+// unit, in the order they apply
+func (m *Manager) UnitFilePaths(unitName string) ([]string, error) {
+	props, err := m.conn.GetUnitProperties(unitName)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	if path, _ := props["FragmentPath"].(string); path != "" {
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s has no unit file", unitName)
+	}
+	dropIns, _ := props["DropInPaths"].([]string)
+	return append(files, dropIns...), nil
+}
+
+// LocalUnitFilePaths returns a unit file and the drop-ins next to it
+// (<file>.d/*.conf), for linting files that aren't installed yet
+func LocalUnitFilePaths(path string) []string {
+	dropIns, _ := filepath.Glob(path + ".d/*.conf")
+	sort.Strings(dropIns)
+	return append([]string{path}, dropIns...)
+}
+
+// LintUnitFiles checks a unit file and its drop-ins, given in the order
+// they apply, for mistakes systemd would reject or silently ignore
+func LintUnitFiles(files []string) ([]types.LintFinding, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	unitType := unitFileType(files[0])
+
+	var findings []types.LintFinding
+	report := func(file string, line int, severity types.LintSeverity, format string, args ...interface{}) {
+		findings = append(findings, types.LintFinding{
+			File: file, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	var all []unitSetting
+	for _, file := range files {
+		settings, err := parseUnitFile(file, unitType, report)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range settings {
+			checkDirective(s, unitType, report)
+			checkExecPath(s, report)
+		}
+		all = append(all, settings...)
+	}
+
+	checkEffectiveSettings(files[0], unitType, all, report)
+	checkDropIns(files[0], all, report)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return fileIndex(files, findings[i].File) < fileIndex(files, findings[j].File)
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// parseUnitFile reads the settings of a unit file, reporting lines that
+// aren't sections, settings or comments, and sections that don't belong
+// in the unit type. Settings in such sections are skipped.
+func parseUnitFile(path, unitType string, report lintReporter) ([]unitSetting, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var settings []unitSetting
+	section, validSection := "", true
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		start := lineNo
+		line := strings.TrimSpace(scanner.Text())
+
+		// A trailing backslash continues the value on the next line
+		for strings.HasSuffix(line, `\`) && scanner.Scan() {
+			lineNo++
+			line = strings.TrimSuffix(line, `\`) + " " + strings.TrimSpace(scanner.Text())
+		}
+
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				report(path, start, types.LintError, "malformed section header %q", line)
+				continue
+			}
+			section = line[1 : len(line)-1]
+			validSection = checkSection(path, start, section, unitType, report)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			report(path, start, types.LintError, "not a key=value setting: %q", line)
+			continue
+		}
+		if section == "" {
+			report(path, start, types.LintError, "%s= is outside of any section", strings.TrimSpace(key))
+			continue
+		}
+		if !validSection {
+			continue
+		}
+		settings = append(settings, unitSetting{
+			File: path, Line: start, Section: section,
+			Key: strings.TrimSpace(key), Value: strings.TrimSpace(value),
+		})
+	}
+	return settings, scanner.Err()
+}
+
+// checkSection reports unknown sections and sections of another unit
+// type, returning whether the section is valid
+func checkSection(file string, line int, section, unitType string, report lintReporter) bool {
+	if strings.HasPrefix(section, "X-") {
+		return true
+	}
+
+	typeSection := unitTypeSections[unitType]
+	if _, ok := sectionDirectives[section]; !ok {
+		if suggestion := closest(section, sectionNames()); suggestion != "" {
+			report(file, line, types.LintError, "unknown section [%s], did you mean [%s]?", section, suggestion)
+		} else {
+			report(file, line, types.LintError, "unknown section [%s]", section)
+		}
+		return false
+	}
+	if section != "Unit" && section != "Install" && typeSection != "" && section != typeSection {
+		report(file, line, types.LintError, "section [%s] doesn't belong in a .%s unit", section, unitType)
+		return false
+	}
+	return true
+}
+
+// checkDirective reports unknown or misplaced keys and deprecated
+// directives
+func checkDirective(s unitSetting, unitType string, report lintReporter) {
+	if strings.HasPrefix(s.Section, "X-") || strings.HasPrefix(s.Key, "X-") {
+		return
+	}
+	known := sectionDirectives[s.Section]
+	typeSection := unitTypeSections[unitType]
+
+	if replacement, ok := deprecatedDirectives[s.Key]; ok {
+		report(s.File, s.Line, types.LintWarning, "%s= is deprecated, use %s", s.Key, replacement)
+		return
+	}
+	if known[s.Key] {
+		return
+	}
+
+	// A known directive in the wrong section, or a typo
+	sections := sectionNames()
+	if typeSection != "" {
+		sections = append([]string{typeSection, "Unit", "Install"}, sections...)
+	}
+	for _, section := range sections {
+		if sectionDirectives[section][s.Key] {
+			report(s.File, s.Line, types.LintError, "%s= belongs in [%s], not [%s]", s.Key, section, s.Section)
+			return
+		}
+	}
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	if suggestion := closest(s.Key, names); suggestion != "" {
+		report(s.File, s.Line, types.LintError, "unknown directive %s=, did you mean %s=?", s.Key, suggestion)
+	} else {
+		report(s.File, s.Line, types.LintError, "unknown directive %s= in [%s]", s.Key, s.Section)
+	}
+}
+
+// checkExecPath reports Exec commands that aren't absolute paths
+func checkExecPath(s unitSetting, report lintReporter) {
+	if !commandLineDirectives[s.Key] || s.Value == "" {
+		return
+	}
+
+	// Strip the special prefixes (@, -, :, +, !)
+	command := strings.TrimLeft(s.Value, "@-:+!")
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return
+	}
+	path := strings.Trim(fields[0], `"'`)
+
+	switch {
+	case strings.HasPrefix(path, "/") || strings.HasPrefix(path, "$"):
+	case strings.Contains(path, "/"):
+		report(s.File, s.Line, types.LintError, "%s= uses the relative path %q; use an absolute path", s.Key, path)
+	default:
+		report(s.File, s.Line, types.LintWarning,
+			"%s= runs %q from systemd's fixed search path, which may differ from your shell's PATH; use an absolute path",
+			s.Key, path)
+	}
+}
+
+// checkEffectiveSettings checks combinations of settings after drop-ins
+// are applied
+func checkEffectiveSettings(unitFile, unitType string, all []unitSetting, report lintReporter) {
+	last := map[string]unitSetting{}
+	install := false
+	for _, s := range all {
+		last[s.Section+"."+s.Key] = s
+		if s.Section == "Install" && s.Value != "" {
+			install = true
+		}
+	}
+
+	if t, ok := last["Service.Type"]; ok && t.Value == "forking" {
+		if _, ok := last["Service.PIDFile"]; !ok {
+			report(t.File, t.Line, types.LintWarning,
+				"Type=forking without PIDFile=; systemd has to guess the main process")
+		}
+	}
+
+	if r, ok := last["Service.Restart"]; ok && r.Value == "always" {
+		if _, ok := last["Service.RestartSec"]; !ok {
+			report(r.File, r.Line, types.LintWarning,
+				"Restart=always without RestartSec=; a crashing service restarts every 100ms until it hits the start limit")
+		}
+	}
+
+	if enableableTypes[unitType] && !install && !strings.Contains(filepath.Base(unitFile), "@.") {
+		report(unitFile, 0, types.LintInfo,
+			"no [Install] section: the unit can't be enabled, only started by hand or pulled in by other units")
+	}
+}
+
+// checkDropIns reports drop-ins that override each other, and ExecStart=
+// lines that add a second command instead of replacing the first
+func checkDropIns(unitFile string, all []unitSetting, report lintReporter) {
+	// Oneshot services may have several ExecStart= commands
+	oneshot := false
+	for _, s := range all {
+		if s.Section == "Service" && s.Key == "Type" {
+			oneshot = s.Value == "oneshot"
+		}
+	}
+
+	setBy := map[string]unitSetting{}
+	execStartReset := map[string]bool{} // Drop-ins with an empty ExecStart=
+	fragmentExecStart := false
+	for _, s := range all {
+		id := s.Section + "." + s.Key
+		if s.File == unitFile {
+			if id == "Service.ExecStart" && s.Value != "" {
+				fragmentExecStart = true
+			}
+			continue
+		}
+
+		if id == "Service.ExecStart" {
+			if s.Value == "" {
+				execStartReset[s.File] = true
+			} else if fragmentExecStart && !execStartReset[s.File] && !oneshot {
+				report(s.File, s.Line, types.LintError,
+					"ExecStart= adds a second command to the one in %s; put an empty ExecStart= before it to replace it",
+					filepath.Base(unitFile))
+			}
+			continue
+		}
+		if isListDirective(s.Key) {
+			continue
+		}
+
+		if prev, ok := setBy[id]; ok && prev.File != s.File && prev.Value != s.Value {
+			report(s.File, s.Line, types.LintWarning, "%s=%s overrides %s=%s from %s:%d",
+				s.Key, s.Value, s.Key, prev.Value, filepath.Base(prev.File), prev.Line)
+		}
+		setBy[id] = s
+	}
+}
+
+// unitFileType returns the unit type of a unit file or drop-in, e.g.
+// "service" for nginx.service or nginx.service.d/override.conf
+func unitFileType(path string) string {
+	name := filepath.Base(path)
+	if strings.HasSuffix(name, ".conf") {
+		name = strings.TrimSuffix(filepath.Base(filepath.Dir(path)), ".d")
+	}
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// sectionNames returns the known sections in a stable order
+func sectionNames() []string {
+	names := make([]string, 0, len(sectionDirectives))
+	for name := range sectionDirectives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// closest returns the candidate nearest to name when it's a likely typo
+// (a different case or at most two edits away), or ""
+func closest(name string, candidates []string) string {
+	best, bestDist := "", 3
+	sort.Strings(candidates)
+	for _, c := range candidates {
+		if strings.EqualFold(c, name) {
+			return c
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// fileIndex returns the position of a file in the lint order
+func fileIndex(files []string, file string) int {
+	for i, f := range files {
+		if f == file {
+			return i
+		}
+	}
+	return len(files)
+}
@@ -0,0 +1,201 @@
+package systemd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+// writeUnitFiles writes files, given as a path relative to a temporary
+// directory and the content, returning their paths in the order given
+func writeUnitFiles(t *testing.T, files [][2]string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for _, f := range files {
+		path := filepath.Join(dir, f[0])
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f[1]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// lintFinding is a finding as expected by the tests: the file's base
+// name, the line and a part of the message
+type lintFinding struct {
+	file     string
+	line     int
+	severity types.LintSeverity
+	message  string
+}
+
+func TestLintUnitFiles(t *testing.T) {
+	const clean = `[Unit]
+Description=Example
+
+[Service]
+ExecStart=/usr/bin/example --serve
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`
+
+	tests := []struct {
+		name  string
+		files [][2]string
+		want  []lintFinding
+	}{
+		{"clean", [][2]string{{"example.service", clean}}, nil},
+		{
+			"typo and misplaced directive",
+			[][2]string{{"example.service", `[Unit]
+Descripton=Example
+ExecStart=/usr/bin/example
+
+[Service]
+ExecStart=/usr/bin/example
+[Install]
+WantedBy=multi-user.target
+`}},
+			[]lintFinding{
+				{"example.service", 2, types.LintError, "did you mean Description=?"},
+				{"example.service", 3, types.LintError, "ExecStart= belongs in [Service], not [Unit]"},
+			},
+		},
+		{
+			"sections",
+			[][2]string{{"example.service", `Description=Orphan
+[Servce]
+ExecStart=/usr/bin/example
+[Timer]
+OnCalendar=daily
+[X-Custom]
+Anything=goes
+`}},
+			[]lintFinding{
+				{"example.service", 0, types.LintInfo, "no [Install] section"},
+				{"example.service", 1, types.LintError, "Description= is outside of any section"},
+				{"example.service", 2, types.LintError, "did you mean [Service]?"},
+				{"example.service", 4, types.LintError, "section [Timer] doesn't belong in a .service unit"},
+			},
+		},
+		{
+			"command paths",
+			[][2]string{{"example.service", `[Service]
+ExecStartPre=-bin/prepare
+ExecStart=example
+ExecStop=/bin/kill $MAINPID
+ExecSearchPath=/opt/example/bin
+[Install]
+WantedBy=multi-user.target
+`}},
+			[]lintFinding{
+				{"example.service", 2, types.LintError, `relative path "bin/prepare"`},
+				{"example.service", 3, types.LintWarning, `runs "example" from systemd's fixed search path`},
+			},
+		},
+		{
+			"effective settings",
+			[][2]string{{"example.service", `[Service]
+Type=forking
+ExecStart=/usr/sbin/exampled
+Restart=always
+MemoryLimit=1G
+[Install]
+WantedBy=multi-user.target
+`}},
+			[]lintFinding{
+				{"example.service", 2, types.LintWarning, "Type=forking without PIDFile="},
+				{"example.service", 4, types.LintWarning, "Restart=always without RestartSec="},
+				{"example.service", 5, types.LintWarning, "MemoryLimit= is deprecated, use MemoryMax="},
+			},
+		},
+		{
+			"drop-ins",
+			[][2]string{
+				{"example.service", clean},
+				{"example.service.d/10-start.conf", "[Service]\nExecStart=/usr/bin/example --other\n"},
+				{"example.service.d/20-replace.conf", "[Service]\nExecStart=\nExecStart=/usr/bin/example --third\n"},
+				{"example.service.d/30-restart.conf", "[Service]\nRestart=on-abnormal\nRestartSec=5\n"},
+				{"example.service.d/40-restart.conf", "[Service]\nRestart=always\nRestartSec=5\n"},
+			},
+			[]lintFinding{
+				{"10-start.conf", 2, types.LintError, "ExecStart= adds a second command"},
+				{"40-restart.conf", 2, types.LintWarning, "Restart=always overrides Restart=on-abnormal from 30-restart.conf:2"},
+			},
+		},
+		{
+			"template without install",
+			[][2]string{{"example@.service", "[Service]\nExecStart=/usr/bin/example %i\n"}},
+			nil,
+		},
+		{
+			"continued line",
+			[][2]string{{"example.timer", `[Timer]
+OnCalendar=Mon \
+  10:00
+Unit=example.service
+Bogus=1
+[Install]
+WantedBy=timers.target
+`}},
+			[]lintFinding{
+				{"example.timer", 5, types.LintError, "unknown directive Bogus= in [Timer]"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := LintUnitFiles(writeUnitFiles(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != len(tt.want) {
+				t.Errorf("got %d findings, want %d:", len(findings), len(tt.want))
+				for _, f := range findings {
+					t.Logf("  %s:%d %s: %s", filepath.Base(f.File), f.Line, f.Severity, f.Message)
+				}
+				return
+			}
+			for i, want := range tt.want {
+				got := findings[i]
+				if filepath.Base(got.File) != want.file || got.Line != want.line ||
+					got.Severity != want.severity || !strings.Contains(got.Message, want.message) {
+					t.Errorf("finding %d is %s:%d %s: %s, want %s:%d %s: ...%s...", i,
+						filepath.Base(got.File), got.Line, got.Severity, got.Message,
+						want.file, want.line, want.severity, want.message)
+				}
+			}
+		})
+	}
+}
+
+func TestLintUnitFilesMissing(t *testing.T) {
+	if _, err := LintUnitFiles([]string{filepath.Join(t.TempDir(), "missing.service")}); err == nil {
+		t.Error("linting a missing file succeeded")
+	}
+}
+
+func TestUnitFileType(t *testing.T) {
+	tests := map[string]string{
+		"/etc/systemd/system/nginx.service":                       "service",
+		"/etc/systemd/system/backup.timer":                        "timer",
+		"/etc/systemd/system/nginx.service.d/override.conf":       "service",
+		"/etc/systemd/system/getty@tty1.service.d/autologin.conf": "service",
+	}
+	for path, want := range tests {
+		if got := unitFileType(path); got != want {
+			t.Errorf("unitFileType(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
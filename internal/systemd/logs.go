@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"sdtop/internal/types"
@@ -21,6 +22,159 @@ type ErrorMsg string
 // LogReader streams journald logs for a specific service
 type LogReader struct {
 	journal *sdjournal.Journal
+	mu      sync.Mutex // Serializes queries sharing the journal cursor
+}
+
+// LogQuery selects which journal entries to read
+type LogQuery struct {
+	// Units lists the units whose entries are merged into one stream
+	Units []string
+
+	// MaxPriority hides entries less severe than this level
+	// (PriorityDebug shows everything)
+	MaxPriority types.Priority
+
+	// BootID limits entries to one boot (empty means all boots)
+	BootID string
+
+	// Since and Until bound the entry timestamps (zero means unbounded)
+	Since time.Time
+	Until time.Time
+
+	// PIDs limits entries to the given processes (empty means all)
+	PIDs []int
+}
+
+// MessageIDCoredump is the MESSAGE_ID of systemd-coredump's crash reports
+const MessageIDCoredump = "fc2e22bc6ee647b6b90729ab34a250b1"
+
+// Fields systemd uses to name the unit an entry is about, rather than the
+// unit that wrote it
+const (
+	fieldUnit         = "UNIT"
+	fieldObjectUnit   = "OBJECT_SYSTEMD_UNIT"
+	fieldCoredumpUnit = "COREDUMP_UNIT"
+)
+
+// applyMatches replaces the journal matches with the ones for a query.
+// Matches on different fields are AND-ed by journald, and matches on the
+// same field are OR-ed, so the priority threshold becomes
+// PRIORITY=0 OR ... OR PRIORITY=<max>. Each unit contributes the same
+// terms as journalctl -u: its own entries, systemd's lifecycle messages
+// about it ("Started", "Main process exited", "Scheduled restart job")
+// and coredump reports. The unit terms are OR-ed together and AND-ed with
+// the boot, PID and priority matches.
+func (lr *LogReader) applyMatches(q LogQuery) error {
+	lr.journal.FlushMatches()
+
+	for _, unit := range q.Units {
+		if err := lr.addUnitMatches(unit); err != nil {
+			return fmt.Errorf("failed to add match: %w", err)
+		}
+	}
+	if err := lr.journal.AddConjunction(); err != nil {
+		return fmt.Errorf("failed to add match: %w", err)
+	}
+
+	if q.BootID != "" {
+		if err := lr.journal.AddMatch(sdjournal.SD_JOURNAL_FIELD_BOOT_ID + "=" + q.BootID); err != nil {
+			return fmt.Errorf("failed to add boot match: %w", err)
+		}
+	}
+
+	for _, pid := range q.PIDs {
+		if err := lr.journal.AddMatch(sdjournal.SD_JOURNAL_FIELD_PID + "=" + strconv.Itoa(pid)); err != nil {
+			return fmt.Errorf("failed to add PID match: %w", err)
+		}
+	}
+
+	if q.MaxPriority < types.PriorityDebug {
+		for p := types.PriorityEmerg; p <= q.MaxPriority; p++ {
+			match := sdjournal.SD_JOURNAL_FIELD_PRIORITY + "=" + strconv.Itoa(int(p))
+			if err := lr.journal.AddMatch(match); err != nil {
+				return fmt.Errorf("failed to add priority match: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// addUnitMatches adds the alternatives matching entries about one unit,
+// each as its own disjunction term
+func (lr *LogReader) addUnitMatches(unit string) error {
+	terms := [][]string{
+		// Messages from the unit's own processes
+		{sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT + "=" + unit},
+		// Coredumps of the unit's processes
+		{
+			sdjournal.SD_JOURNAL_FIELD_MESSAGE_ID + "=" + MessageIDCoredump,
+			sdjournal.SD_JOURNAL_FIELD_UID + "=0",
+			fieldCoredumpUnit + "=" + unit,
+		},
+		// Messages from systemd about the unit
+		{sdjournal.SD_JOURNAL_FIELD_PID + "=1", fieldUnit + "=" + unit},
+		// Messages from other privileged daemons about the unit
+		{sdjournal.SD_JOURNAL_FIELD_UID + "=0", fieldObjectUnit + "=" + unit},
+	}
+
+	for _, term := range terms {
+		for _, match := range term {
+			if err := lr.journal.AddMatch(match); err != nil {
+				return err
+			}
+		}
+		if err := lr.journal.AddDisjunction(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryFromJournal converts a raw journal entry into a LogEntry
+func entryFromJournal(entry *sdjournal.JournalEntry) types.LogEntry {
+	// Entries without a valid PRIORITY are treated as info, like journalctl does
+	priority, ok := types.ParsePriority(entry.Fields[sdjournal.SD_JOURNAL_FIELD_PRIORITY])
+	if !ok {
+		priority = types.PriorityInfo
+	}
+
+	// Keep the address fields alongside the data fields, like journalctl -o export
+	entry.Fields[sdjournal.SD_JOURNAL_FIELD_CURSOR] = entry.Cursor
+	entry.Fields[sdjournal.SD_JOURNAL_FIELD_REALTIME_TIMESTAMP] = strconv.FormatUint(entry.RealtimeTimestamp, 10)
+	entry.Fields[sdjournal.SD_JOURNAL_FIELD_MONOTONIC_TIMESTAMP] = strconv.FormatUint(entry.MonotonicTimestamp, 10)
+
+	unit, source := entrySource(entry.Fields)
+
+	return types.LogEntry{
+		Timestamp: usecToTime(entry.RealtimeTimestamp),
+		Message:   entry.Fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE],
+		Priority:  priority,
+		Cursor:    entry.Cursor,
+		Unit:      unit,
+		Source:    source,
+		Fields:    entry.Fields,
+	}
+}
+
+// entrySource finds the unit an entry is about and who wrote it. Entries
+// from systemd or systemd-coredump are attributed to the unit they
+// describe instead of init.scope or systemd-coredump@.service.
+func entrySource(fields map[string]string) (string, types.LogSource) {
+	if fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE_ID] == MessageIDCoredump {
+		if unit := fields[fieldCoredumpUnit]; unit != "" {
+			return unit, types.SourceCoredump
+		}
+	}
+	if fields[sdjournal.SD_JOURNAL_FIELD_PID] == "1" {
+		if unit := fields[fieldUnit]; unit != "" {
+			return unit, types.SourceManager
+		}
+	}
+	if unit := fields[fieldObjectUnit]; unit != "" && fields[sdjournal.SD_JOURNAL_FIELD_UID] == "0" {
+		return unit, types.SourceManager
+	}
+	return fields[sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT], types.SourceUnit
 }
 
 // NewLogReader creates a new log reader
@@ -85,16 +239,7 @@ func (lr *LogReader) followLogs(ctx context.Context) {
 					continue
 				}
 
-				// Extract fields
-				message := entry.Fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE]
-				priority := entry.Fields[sdjournal.SD_JOURNAL_FIELD_PRIORITY]
-				timestamp := time.Unix(0, int64(entry.RealtimeTimestamp)*1000)
-
-				logEntry := types.LogEntry{
-					Timestamp: timestamp,
-					Message:   message,
-					Priority:  priority,
-				}
+				logEntry := entryFromJournal(entry)
 
 				// This is a simplified version - in real implementation,
 				// we'd send this through a channel to the UI
@@ -107,32 +252,137 @@ func (lr *LogReader) followLogs(ctx context.Context) {
 	}
 }
 
-// GetRecentLogs retrieves recent logs for a service
-func (lr *LogReader) GetRecentLogs(serviceName string, count int) ([]types.LogEntry, error) {
-	// Clear any previous matches
-	lr.journal.FlushMatches()
+// GetRecentLogs retrieves the last 'count' entries matching a query.
+// If the query has a time range, the entries are the most recent ones
+// inside it.
+func (lr *LogReader) GetRecentLogs(q LogQuery, count int) ([]types.LogEntry, error) {
+	// Without units there is nothing to match (and no match means everything)
+	if len(q.Units) == 0 {
+		return nil, nil
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
 
-	// Add match for the specific service
-	if err := lr.journal.AddMatch("_SYSTEMD_UNIT=" + serviceName); err != nil {
-		return nil, fmt.Errorf("failed to add match: %w", err)
+	// Replace any previous matches
+	if err := lr.applyMatches(q); err != nil {
+		return nil, err
 	}
 
-	// Seek to tail
-	if err := lr.journal.SeekTail(); err != nil {
-		return nil, fmt.Errorf("failed to seek tail: %w", err)
+	// Seek to the end of the range
+	if q.Until.IsZero() {
+		if err := lr.journal.SeekTail(); err != nil {
+			return nil, fmt.Errorf("failed to seek tail: %w", err)
+		}
+	} else {
+		if err := lr.journal.SeekRealtimeUsec(uint64(q.Until.UnixMicro())); err != nil {
+			return nil, fmt.Errorf("failed to seek to %s: %w", q.Until, err)
+		}
 	}
 
-	// Go back 'count' entries
-	for i := 0; i < count; i++ {
-		if n, err := lr.journal.Previous(); err != nil || n == 0 {
+	var logs []types.LogEntry
+
+	// Read backwards up to 'count' entries
+	for len(logs) < count {
+		n, err := lr.journal.Previous()
+		if err != nil || n == 0 {
+			break
+		}
+
+		entry, err := lr.journal.GetEntry()
+		if err != nil {
+			continue
+		}
+
+		log := entryFromJournal(entry)
+		if !q.Since.IsZero() && log.Timestamp.Before(q.Since) {
 			break
 		}
+		logs = append(logs, log)
+	}
+
+	// Return oldest first
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	return logs, nil
+}
+
+// GetLogsBefore retrieves up to 'count' entries older than the entry at
+// cursor, oldest first. Fewer than 'count' entries means the start of the
+// journal (or of the query's time range) was reached.
+func (lr *LogReader) GetLogsBefore(q LogQuery, cursor string, count int) ([]types.LogEntry, error) {
+	// Without units there is nothing to match (and no match means everything)
+	if len(q.Units) == 0 {
+		return nil, nil
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if err := lr.applyMatches(q); err != nil {
+		return nil, err
+	}
+
+	if err := lr.journal.SeekCursor(cursor); err != nil {
+		return nil, fmt.Errorf("failed to seek cursor: %w", err)
 	}
 
 	var logs []types.LogEntry
+	for len(logs) < count {
+		n, err := lr.journal.Previous()
+		if err != nil || n == 0 {
+			break
+		}
 
-	// Read forward up to 'count' entries
-	for i := 0; i < count; i++ {
+		entry, err := lr.journal.GetEntry()
+		if err != nil {
+			continue
+		}
+
+		// Seeking lands on the cursor's own entry, which we already have
+		if entry.Cursor == cursor {
+			continue
+		}
+
+		log := entryFromJournal(entry)
+		if !q.Since.IsZero() && log.Timestamp.Before(q.Since) {
+			break
+		}
+		logs = append(logs, log)
+	}
+
+	// Return oldest first
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	return logs, nil
+}
+
+// GetLogsAfter retrieves up to 'count' entries newer than the entry at
+// cursor. Fewer than 'count' entries means the end of the journal (or of
+// the query's time range) was reached.
+func (lr *LogReader) GetLogsAfter(q LogQuery, cursor string, count int) ([]types.LogEntry, error) {
+	// Without units there is nothing to match (and no match means everything)
+	if len(q.Units) == 0 {
+		return nil, nil
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if err := lr.applyMatches(q); err != nil {
+		return nil, err
+	}
+
+	if err := lr.journal.SeekCursor(cursor); err != nil {
+		return nil, fmt.Errorf("failed to seek cursor: %w", err)
+	}
+
+	var logs []types.LogEntry
+	for len(logs) < count {
 		n, err := lr.journal.Next()
 		if err != nil || n == 0 {
 			break
@@ -143,29 +393,35 @@ func (lr *LogReader) GetRecentLogs(serviceName string, count int) ([]types.LogEn
 			continue
 		}
 
-		message := entry.Fields[sdjournal.SD_JOURNAL_FIELD_MESSAGE]
-		priorityStr := entry.Fields[sdjournal.SD_JOURNAL_FIELD_PRIORITY]
-		timestamp := time.Unix(0, int64(entry.RealtimeTimestamp)*1000)
-
-		// Parse priority
-		priority := "info"
-		if p, err := strconv.Atoi(priorityStr); err == nil {
-			switch {
-			case p <= 3:
-				priority = "error"
-			case p <= 4:
-				priority = "warn"
-			default:
-				priority = "info"
-			}
+		// Seeking lands on the cursor's own entry, which we already have
+		if entry.Cursor == cursor {
+			continue
 		}
 
-		logs = append(logs, types.LogEntry{
-			Timestamp: timestamp,
-			Message:   message,
-			Priority:  priority,
-		})
+		log := entryFromJournal(entry)
+		if !q.Until.IsZero() && !log.Timestamp.Before(q.Until) {
+			break
+		}
+		logs = append(logs, log)
 	}
 
 	return logs, nil
 }
+
+// GetCatalog returns the message catalog text for the entry at cursor
+// (what journalctl -x shows), with the entry's fields substituted. Entries
+// without a catalog entry return an error.
+func (lr *LogReader) GetCatalog(cursor string) (string, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.journal.FlushMatches()
+	if err := lr.journal.SeekCursor(cursor); err != nil {
+		return "", fmt.Errorf("failed to seek cursor: %w", err)
+	}
+	if n, err := lr.journal.Next(); err != nil || n == 0 {
+		return "", fmt.Errorf("entry not found")
+	}
+
+	return lr.journal.GetCatalog()
+}
@@ -6,20 +6,26 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"sdtop/internal/types"
 )
 
 // ProcessManager handles process tree operations
-type ProcessManager struct{}
+type ProcessManager struct {
+	mu      sync.Mutex
+	samples map[string]map[int]cpuSample // Previous thread CPU time by unit and TID
+}
 
 // NewProcessManager creates a new process manager
 func NewProcessManager() *ProcessManager {
-	return &ProcessManager{}
+	return &ProcessManager{samples: map[string]map[int]cpuSample{}}
 }
 
-// GetServiceProcesses returns the process tree for a service
-func (pm *ProcessManager) GetServiceProcesses(serviceName string) ([]*types.Process, error) {
+// GetServiceProcesses returns the process tree for a service. Threads are
+// read only for the processes in threadPIDs, which may be nil.
+func (pm *ProcessManager) GetServiceProcesses(serviceName string, threadPIDs map[int]bool) ([]*types.Process, error) {
 	// Get all PIDs for this service
 	pids := pm.getAllServicePIDs(serviceName)
 
@@ -32,15 +38,31 @@ func (pm *ProcessManager) GetServiceProcesses(serviceName string) ([]*types.Proc
 	processMap := make(map[int]*types.Process)
 
 	// First pass: create all process objects
+	pm.mu.Lock()
+	prev := pm.samples[serviceName]
+	pm.mu.Unlock()
+	samples := make(map[int]cpuSample)
+	now := time.Now()
 	for _, pid := range pids {
 		proc, err := pm.getProcessInfo(pid)
 		if err != nil {
 			continue
 		}
+		if threadPIDs[pid] {
+			proc.Threads = getThreads(pid, now, prev, samples)
+		}
 		processMap[pid] = proc
 		processes = append(processes, proc)
 	}
 
+	// Threads that exited or are no longer shown are forgotten; a call
+	// without threads leaves the samples alone
+	if len(threadPIDs) > 0 {
+		pm.mu.Lock()
+		pm.samples[serviceName] = samples
+		pm.mu.Unlock()
+	}
+
 	// Second pass: build parent-child relationships
 	var roots []*types.Process
 	for _, proc := range processes {
@@ -199,7 +221,7 @@ func (pm *ProcessManager) getProcessInfo(pid int) (*types.Process, error) {
 	endIdx := strings.LastIndex(statStr, ")")
 
 	var name string
-	var ppid int
+	var ppid, numThreads int
 
 	if startIdx != -1 && endIdx != -1 {
 		name = statStr[startIdx+1 : endIdx]
@@ -208,6 +230,10 @@ func (pm *ProcessManager) getProcessInfo(pid int) (*types.Process, error) {
 		if len(fields) >= 2 {
 			ppid, _ = strconv.Atoi(fields[1])
 		}
+		// num_threads is the 20th field
+		if len(fields) >= 18 {
+			numThreads, _ = strconv.Atoi(fields[17])
+		}
 	}
 
 	if cmdline == "" {
@@ -215,10 +241,11 @@ func (pm *ProcessManager) getProcessInfo(pid int) (*types.Process, error) {
 	}
 
 	return &types.Process{
-		PID:     pid,
-		Name:    name,
-		Cmdline: cmdline,
-		Parent:  ppid,
+		PID:        pid,
+		Name:       name,
+		Cmdline:    cmdline,
+		Parent:     ppid,
+		NumThreads: numThreads,
 	}, nil
 }
 
@@ -0,0 +1,233 @@
+package systemd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// dangerousCaps are the capabilities weighed by the CapabilityBoundingSet
+// check, by bit number (see capabilities(7))
+var dangerousCaps = []struct {
+	bit  uint
+	name string
+}{
+	{21, "CAP_SYS_ADMIN"},
+	{19, "CAP_SYS_PTRACE"},
+	{16, "CAP_SYS_MODULE"},
+	{17, "CAP_SYS_RAWIO"},
+	{12, "CAP_NET_ADMIN"},
+	{13, "CAP_NET_RAW"},
+	{39, "CAP_BPF"},
+	{22, "CAP_SYS_BOOT"},
+	{25, "CAP_SYS_TIME"},
+	{1, "CAP_DAC_OVERRIDE"},
+	{2, "CAP_DAC_READ_SEARCH"},
+	{3, "CAP_FOWNER"},
+	{0, "CAP_CHOWN"},
+	{6, "CAP_SETGID"},
+	{7, "CAP_SETUID"},
+	{5, "CAP_KILL"},
+}
+
+// boolChecks are sandboxing switches that are either on or off
+var boolChecks = []struct {
+	name   string
+	weight int
+	what   string
+}{
+	{"NoNewPrivileges", 1000, "can gain privileges through setuid binaries"},
+	{"PrivateTmp", 1000, "shares /tmp with the rest of the system"},
+	{"PrivateDevices", 1000, "can access hardware devices"},
+	{"ProtectKernelTunables", 1000, "can change kernel tunables in /proc/sys"},
+	{"ProtectKernelModules", 1000, "can load kernel modules"},
+	{"ProtectKernelLogs", 1000, "can read the kernel log"},
+	{"ProtectControlGroups", 1000, "can modify control groups"},
+	{"ProtectClock", 1000, "can change the system clock"},
+	{"RestrictSUIDSGID", 1000, "can create setuid/setgid files"},
+	{"RestrictRealtime", 500, "can take realtime scheduling"},
+	{"PrivateNetwork", 500, "has access to the host network"},
+	{"LockPersonality", 100, "can change its execution domain"},
+	{"MemoryDenyWriteExecute", 100, "can create writable and executable memory"},
+	{"ProtectHostname", 50, "can change the hostname"},
+}
+
+// AnalyzeSecurity evaluates a service's sandboxing settings
+func (m *Manager) AnalyzeSecurity(unitName string) (*types.SecurityReport, error) {
+	if !strings.HasSuffix(unitName, ".service") {
+		return nil, fmt.Errorf("%s is not a service", unitName)
+	}
+	props, err := m.GetUnitProperties(unitName)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateSecurity(unitName, props), nil
+}
+
+// EvaluateSecurity scores a service from its properties. Each check has a
+// weight and an exposure between 0 and 1; the score is the weighted mean
+// exposure scaled to 0-10, like systemd-analyze security.
+func EvaluateSecurity(unitName string, props map[string]interface{}) *types.SecurityReport {
+	var checks []types.SecurityCheck
+	add := func(name string, weight int, exposure float64, desc, fix string) {
+		checks = append(checks, types.SecurityCheck{
+			Name: name, Weight: weight, Exposure: exposure, Description: desc, Fix: fix,
+		})
+	}
+
+	// Running as root outweighs everything else
+	user, _ := props["User"].(string)
+	dynamic, _ := props["DynamicUser"].(bool)
+	switch {
+	case dynamic:
+		add("User", 2000, 0, "runs as a dynamic user", "")
+	case user == "" || user == "root" || user == "0":
+		add("User", 2000, 1, "runs as root", "DynamicUser=yes or User=<unprivileged user>")
+	default:
+		add("User", 2000, 0, "runs as "+user, "")
+	}
+
+	switch protect, _ := props["ProtectSystem"].(string); protect {
+	case "strict":
+		add("ProtectSystem", 1000, 0, "the whole file system is read-only", "")
+	case "full":
+		add("ProtectSystem", 1000, 0.1, "/usr, /boot and /etc are read-only", "ProtectSystem=strict")
+	case "yes", "true":
+		add("ProtectSystem", 1000, 0.5, "/usr and /boot are read-only", "ProtectSystem=strict")
+	default:
+		add("ProtectSystem", 1000, 1, "can write to system directories", "ProtectSystem=strict")
+	}
+
+	switch protect, _ := props["ProtectHome"].(string); protect {
+	case "yes", "true", "tmpfs":
+		add("ProtectHome", 1000, 0, "home directories are hidden", "")
+	case "read-only":
+		add("ProtectHome", 1000, 0.2, "home directories are read-only", "ProtectHome=yes")
+	default:
+		add("ProtectHome", 1000, 1, "can access home directories", "ProtectHome=yes")
+	}
+
+	switch protect, _ := props["ProtectProc"].(string); protect {
+	case "invisible", "noaccess", "ptraceable":
+		add("ProtectProc", 1000, 0, "other users' processes are hidden", "")
+	default:
+		add("ProtectProc", 1000, 1, "can see all processes in /proc", "ProtectProc=invisible")
+	}
+
+	for _, c := range boolChecks {
+		if on, _ := props[c.name].(bool); on {
+			add(c.name, c.weight, 0, "enabled", "")
+		} else {
+			add(c.name, c.weight, 1, c.what, c.name+"=yes")
+		}
+	}
+
+	// Capabilities: the share of dangerous ones still in the bounding set
+	caps, ok := props["CapabilityBoundingSet"].(uint64)
+	if !ok {
+		caps = math.MaxUint64
+	}
+	var kept []string
+	for _, c := range dangerousCaps {
+		if caps&(1<<c.bit) != 0 {
+			kept = append(kept, c.name)
+		}
+	}
+	if len(kept) == 0 {
+		add("CapabilityBoundingSet", 1500, 0, "no dangerous capabilities", "")
+	} else {
+		add("CapabilityBoundingSet", 1500, float64(len(kept))/float64(len(dangerousCaps)),
+			fmt.Sprintf("keeps %d dangerous capabilities", len(kept)),
+			"CapabilityBoundingSet=~"+strings.Join(kept, " "))
+	}
+
+	// Allow or deny lists, reported by systemd as (allow-list, entries)
+	allow, families := listProp(props, "RestrictAddressFamilies")
+	switch {
+	case allow && len(families) > 0:
+		add("RestrictAddressFamilies", 1000, 0, "limited to "+strings.Join(families, " "), "")
+	case len(families) > 0:
+		add("RestrictAddressFamilies", 1000, 0.5, "some socket families are denied",
+			"RestrictAddressFamilies=AF_UNIX AF_INET AF_INET6")
+	default:
+		add("RestrictAddressFamilies", 1000, 1, "can use any socket family",
+			"RestrictAddressFamilies=AF_UNIX AF_INET AF_INET6")
+	}
+
+	allow, syscalls := listProp(props, "SystemCallFilter")
+	switch {
+	case allow && len(syscalls) > 0:
+		add("SystemCallFilter", 1000, 0, "system calls are allow-listed", "")
+	case len(syscalls) > 0:
+		add("SystemCallFilter", 1000, 0.5, "some system calls are denied", "SystemCallFilter=@system-service")
+	default:
+		add("SystemCallFilter", 1000, 1, "can use any system call", "SystemCallFilter=@system-service")
+	}
+
+	if archs, _ := props["SystemCallArchitectures"].([]string); len(archs) > 0 {
+		add("SystemCallArchitectures", 1000, 0, "limited to "+strings.Join(archs, " "), "")
+	} else {
+		add("SystemCallArchitectures", 1000, 1, "can use system calls of any architecture",
+			"SystemCallArchitectures=native")
+	}
+
+	// RestrictNamespaces holds the namespace types still allowed
+	if ns, ok := props["RestrictNamespaces"].(uint64); ok && ns == 0 {
+		add("RestrictNamespaces", 1000, 0, "cannot create namespaces", "")
+	} else {
+		add("RestrictNamespaces", 1000, 1, "can create namespaces", "RestrictNamespaces=yes")
+	}
+
+	if umask, ok := props["UMask"].(uint32); ok && umask&0o002 == 0 {
+		add("UMask", 100, 1, fmt.Sprintf("creates world-writable files (UMask=%04o)", umask), "UMask=0027")
+	} else {
+		add("UMask", 100, 0, "files are not world-writable", "")
+	}
+
+	report := &types.SecurityReport{Unit: unitName}
+	var total, exposed float64
+	for _, c := range checks {
+		total += float64(c.Weight)
+		exposed += float64(c.Weight) * c.Exposure
+	}
+	report.Score = math.Round(exposed/total*100) / 10
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return float64(checks[i].Weight)*checks[i].Exposure > float64(checks[j].Weight)*checks[j].Exposure
+	})
+	report.Checks = checks
+	return report
+}
+
+// ExposureLevel names a score like systemd-analyze security
+func ExposureLevel(score float64) string {
+	switch {
+	case score >= 9:
+		return "UNSAFE"
+	case score >= 7.5:
+		return "EXPOSED"
+	case score >= 5:
+		return "MEDIUM"
+	case score >= 1:
+		return "OK"
+	case score > 0:
+		return "SAFE"
+	default:
+		return "PERFECT"
+	}
+}
+
+// listProp reads an allow/deny list property, which D-Bus returns as a
+// (bool, []string) structure
+func listProp(props map[string]interface{}, key string) (bool, []string) {
+	value, ok := props[key].([]interface{})
+	if !ok || len(value) != 2 {
+		return false, nil
+	}
+	allow, _ := value[0].(bool)
+	entries, _ := value[1].([]string)
+	return allow, entries
+}
@@ -0,0 +1,120 @@
+package systemd
+
+import (
+	"math"
+	"testing"
+)
+
+// hardenedProps are the properties of a service with every check passing
+func hardenedProps() map[string]interface{} {
+	props := map[string]interface{}{
+		"DynamicUser":             true,
+		"ProtectSystem":           "strict",
+		"ProtectHome":             "yes",
+		"ProtectProc":             "invisible",
+		"CapabilityBoundingSet":   uint64(0),
+		"RestrictAddressFamilies": []interface{}{true, []string{"AF_UNIX"}},
+		"SystemCallFilter":        []interface{}{true, []string{"read", "write"}},
+		"SystemCallArchitectures": []string{"native"},
+		"RestrictNamespaces":      uint64(0),
+		"UMask":                   uint32(0o027),
+	}
+	for _, c := range boolChecks {
+		props[c.name] = true
+	}
+	return props
+}
+
+func TestEvaluateSecurity(t *testing.T) {
+	tests := []struct {
+		name   string
+		change map[string]interface{} // Applied over hardenedProps
+		score  float64
+		level  string
+	}{
+		{"hardened", nil, 0, "PERFECT"},
+		{"root user", map[string]interface{}{"DynamicUser": false}, 1.0, "OK"},
+		{"named user", map[string]interface{}{"DynamicUser": false, "User": "www-data"}, 0, "PERFECT"},
+		{"root without ProtectHome", map[string]interface{}{"DynamicUser": false, "ProtectHome": "no"}, 1.4, "OK"},
+		{"ProtectSystem=yes", map[string]interface{}{"ProtectSystem": "yes"}, 0.2, "SAFE"},
+		{"deny-listed families", map[string]interface{}{
+			"RestrictAddressFamilies": []interface{}{false, []string{"AF_PACKET"}},
+		}, 0.2, "SAFE"},
+	}
+
+	for _, tt := range tests {
+		props := hardenedProps()
+		for k, v := range tt.change {
+			props[k] = v
+		}
+		report := EvaluateSecurity("test.service", props)
+		if report.Score != tt.score {
+			t.Errorf("%s: score %.1f, want %.1f", tt.name, report.Score, tt.score)
+		}
+		if level := ExposureLevel(report.Score); level != tt.level {
+			t.Errorf("%s: level %s, want %s", tt.name, level, tt.level)
+		}
+	}
+}
+
+func TestEvaluateSecurityDefaults(t *testing.T) {
+	// A unit with no sandboxing at all is fully exposed
+	report := EvaluateSecurity("test.service", map[string]interface{}{})
+	if report.Score != 10 {
+		t.Errorf("score %.1f, want 10", report.Score)
+	}
+	if level := ExposureLevel(report.Score); level != "UNSAFE" {
+		t.Errorf("level %s, want UNSAFE", level)
+	}
+	if report.Checks[0].Name != "User" {
+		t.Errorf("most exposing check is %s, want User", report.Checks[0].Name)
+	}
+	for i := 1; i < len(report.Checks); i++ {
+		prev, c := report.Checks[i-1], report.Checks[i]
+		if float64(prev.Weight)*prev.Exposure < float64(c.Weight)*c.Exposure {
+			t.Errorf("%s sorted before the more exposing %s", prev.Name, c.Name)
+		}
+	}
+}
+
+func TestEvaluateSecurityCapabilities(t *testing.T) {
+	props := hardenedProps()
+	props["CapabilityBoundingSet"] = uint64(1<<21 | 1<<12) // CAP_SYS_ADMIN, CAP_NET_ADMIN
+
+	for _, c := range EvaluateSecurity("test.service", props).Checks {
+		if c.Name != "CapabilityBoundingSet" {
+			continue
+		}
+		if want := 2.0 / float64(len(dangerousCaps)); math.Abs(c.Exposure-want) > 1e-9 {
+			t.Errorf("exposure %f, want %f", c.Exposure, want)
+		}
+		if want := "CapabilityBoundingSet=~CAP_SYS_ADMIN CAP_NET_ADMIN"; c.Fix != want {
+			t.Errorf("fix %q, want %q", c.Fix, want)
+		}
+		return
+	}
+	t.Error("no CapabilityBoundingSet check")
+}
+
+func TestExposureLevel(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{10, "UNSAFE"},
+		{9, "UNSAFE"},
+		{8.9, "EXPOSED"},
+		{7.5, "EXPOSED"},
+		{5, "MEDIUM"},
+		{4.9, "OK"},
+		{1, "OK"},
+		{0.1, "SAFE"},
+		{0, "PERFECT"},
+	}
+
+	for _, tt := range tests {
+		if got := ExposureLevel(tt.score); got != tt.want {
+			t.Errorf("ExposureLevel(%.1f) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package systemd
 
 import (
 	"strings"
+	"sync"
 
 	"sdtop/internal/types"
 
@@ -11,6 +12,9 @@ import (
 // Manager handles systemd service operations
 type Manager struct {
 	conn *dbus.Conn
+
+	mu       sync.Mutex
+	launched map[string]bool // Transient units started through sdtop
 }
 
 // NewManager creates a new systemd manager
@@ -19,7 +23,7 @@ func NewManager() (*Manager, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Manager{conn: conn}, nil
+	return &Manager{conn: conn, launched: map[string]bool{}}, nil
 }
 
 // Close closes the DBus connection
@@ -36,10 +40,13 @@ func (m *Manager) ListServices() ([]types.Service, error) {
 		return nil, err
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	var services []types.Service
 	for _, unit := range units {
-		// Filter only .service units
-		if !strings.HasSuffix(unit.Name, ".service") {
+		// Filter only .service units, and the scopes of ad-hoc commands
+		if !strings.HasSuffix(unit.Name, ".service") && !IsTransientRun(unit.Name) && !m.launched[unit.Name] {
 			continue
 		}
 
@@ -97,3 +104,26 @@ func (m *Manager) DisableService(serviceName string) error {
 func (m *Manager) GetServiceProperty(serviceName, property string) (interface{}, error) {
 	return m.conn.GetServiceProperty(serviceName, property)
 }
+
+// GetUnitProperties returns all properties of a unit, including the ones
+// specific to its type (e.g. ExecStart for services)
+func (m *Manager) GetUnitProperties(unitName string) (map[string]interface{}, error) {
+	props, err := m.conn.GetUnitProperties(unitName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The D-Bus interface is named after the capitalized suffix ("Service")
+	if i := strings.LastIndex(unitName, "."); i >= 0 && i+1 < len(unitName) {
+		suffix := unitName[i+1:]
+		unitType := strings.ToUpper(suffix[:1]) + suffix[1:]
+		typeProps, err := m.conn.GetUnitTypeProperties(unitName, unitType)
+		if err == nil {
+			for k, v := range typeProps {
+				props[k] = v
+			}
+		}
+	}
+
+	return props, nil
+}
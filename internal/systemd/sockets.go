@@ -0,0 +1,295 @@
+package systemd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sdtop/internal/types"
+)
+
+// inetProtocols are the /proc/net tables of internet sockets
+var inetProtocols = []string{"tcp", "tcp6", "udp", "udp6"}
+
+// tcpStates names the st column of /proc/net/tcp (include/net/tcp_states.h)
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN-SENT",
+	"03": "SYN-RECV",
+	"04": "FIN-WAIT-1",
+	"05": "FIN-WAIT-2",
+	"06": "TIME-WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE-WAIT",
+	"09": "LAST-ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// unixAcceptConn is the __SO_ACCEPTCON flag of a listening unix socket
+const unixAcceptConn = 0x10000
+
+// GetServiceSockets returns the sockets opened by the processes of a
+// service, listening ones first
+func (pm *ProcessManager) GetServiceSockets(serviceName string) (*types.SocketReport, error) {
+	pids := pm.getAllServicePIDs(serviceName)
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("service not running or no processes found")
+	}
+	return pm.socketsOf(pids), nil
+}
+
+// ListeningOn finds the processes listening on a port, in any unit
+func (pm *ProcessManager) ListeningOn(port int) (*types.SocketReport, error) {
+	procDir, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, entry := range procDir {
+		if pid, err := strconv.Atoi(entry.Name()); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+
+	all := pm.socketsOf(pids)
+	report := &types.SocketReport{Unreadable: all.Unreadable}
+	for _, s := range all.Sockets {
+		if s.Listening && s.LocalPort == port && s.Protocol != "unix" {
+			report.Sockets = append(report.Sockets, s)
+		}
+	}
+	return report, nil
+}
+
+// socketsOf maps the socket file descriptors of processes to the
+// endpoints in the socket tables of their network namespaces
+func (pm *ProcessManager) socketsOf(pids []int) *types.SocketReport {
+	report := &types.SocketReport{}
+	tables := map[string]map[uint64]types.Socket{} // By network namespace
+
+	for _, pid := range pids {
+		inodes, err := socketInodes(pid)
+		if err != nil {
+			report.Unreadable++
+			continue
+		}
+		if len(inodes) == 0 {
+			continue
+		}
+
+		// Each network namespace has its own tables, read through any of
+		// its processes
+		netns, _ := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+		table, ok := tables[netns]
+		if !ok {
+			table = readSocketTables(pid)
+			tables[netns] = table
+		}
+
+		name := ""
+		if proc, err := pm.getProcessInfo(pid); err == nil {
+			name = proc.Name
+		}
+		unit := unitOfPID(pid)
+
+		for _, inode := range inodes {
+			s, ok := table[inode]
+			if !ok {
+				continue
+			}
+			s.PID, s.Process, s.Unit = pid, name, unit
+			report.Sockets = append(report.Sockets, s)
+		}
+	}
+
+	sort.SliceStable(report.Sockets, func(i, j int) bool {
+		a, b := report.Sockets[i], report.Sockets[j]
+		if a.Listening != b.Listening {
+			return a.Listening
+		}
+		if (a.Protocol == "unix") != (b.Protocol == "unix") {
+			return b.Protocol == "unix"
+		}
+		if a.LocalPort != b.LocalPort {
+			return a.LocalPort < b.LocalPort
+		}
+		return a.PID < b.PID
+	})
+	return report
+}
+
+// socketInodes lists the inodes of a process's socket file descriptors
+func socketInodes(pid int) ([]uint64, error) {
+	dir := fmt.Sprintf("/proc/%d/fd", pid)
+	fds, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var inodes []uint64
+	for _, fd := range fds {
+		target, err := os.Readlink(dir + "/" + fd.Name())
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		if inode, err := strconv.ParseUint(strings.Trim(target[len("socket:"):], "[]"), 10, 64); err == nil {
+			inodes = append(inodes, inode)
+		}
+	}
+	return inodes, nil
+}
+
+// readSocketTables reads the internet and unix socket tables of a
+// process's network namespace, keyed by inode
+func readSocketTables(pid int) map[uint64]types.Socket {
+	table := map[uint64]types.Socket{}
+	for _, proto := range inetProtocols {
+		readInetTable(fmt.Sprintf("/proc/%d/net/%s", pid, proto), proto, table)
+	}
+	readUnixTable(fmt.Sprintf("/proc/%d/net/unix", pid), table)
+	return table
+}
+
+// readInetTable parses /proc/net/{tcp,udp}[6]:
+// sl local_address rem_address st tx_queue:rx_queue tr:when retrnsmt uid timeout inode
+func readInetTable(path, proto string, table map[uint64]types.Socket) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		localIP, localPort, err := parseHexEndpoint(fields[1])
+		if err != nil {
+			continue
+		}
+		remoteIP, remotePort, err := parseHexEndpoint(fields[2])
+		if err != nil {
+			continue
+		}
+
+		s := types.Socket{
+			Protocol:  proto,
+			Local:     net.JoinHostPort(localIP.String(), strconv.Itoa(localPort)),
+			LocalPort: localPort,
+			State:     tcpStates[fields[3]],
+			Inode:     inode,
+		}
+		if remotePort != 0 {
+			s.Remote = net.JoinHostPort(remoteIP.String(), strconv.Itoa(remotePort))
+		}
+		if strings.HasPrefix(proto, "udp") {
+			// Unconnected datagram sockets are reported as closed
+			s.Listening = s.Remote == ""
+			s.State = "ESTAB"
+			if s.Listening {
+				s.State = "UNCONN"
+			}
+		} else {
+			s.Listening = s.State == "LISTEN"
+		}
+		table[inode] = s
+	}
+}
+
+// parseHexEndpoint decodes an address:port pair from /proc/net, where the
+// address is written as 32-bit words in host byte order
+func parseHexEndpoint(s string) (net.IP, int, error) {
+	addr, port, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("malformed endpoint %q", s)
+	}
+	raw, err := hex.DecodeString(addr)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("malformed address %q", addr)
+	}
+	p, err := strconv.ParseUint(port, 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.NativeEndian.Uint32(raw[i:]))
+	}
+	return ip, int(p), nil
+}
+
+// readUnixTable parses /proc/net/unix:
+// Num RefCount Protocol Flags Type St Inode Path
+func readUnixTable(path string, table map[uint64]types.Socket) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil {
+			continue
+		}
+		flags, _ := strconv.ParseUint(fields[3], 16, 32)
+
+		s := types.Socket{Protocol: "unix", Inode: inode, State: "CONNECTED"}
+		if len(fields) > 7 {
+			s.Local = fields[7]
+		}
+		if flags&unixAcceptConn != 0 {
+			s.Listening, s.State = true, "LISTEN"
+		} else if fields[5] == "01" {
+			s.State = "UNCONN"
+		}
+		table[inode] = s
+	}
+}
+
+// unitOfPID returns the unit a process runs in, from its cgroup path
+func unitOfPID(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+
+	// The unified hierarchy's line is "0::/system.slice/nginx.service"
+	unit := ""
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, segment := range strings.Split(parts[2], "/") {
+			if strings.HasSuffix(segment, ".service") || strings.HasSuffix(segment, ".scope") {
+				unit = segment
+			}
+		}
+		if unit != "" {
+			break
+		}
+	}
+	return unit
+}
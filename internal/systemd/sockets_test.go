@@ -0,0 +1,118 @@
+package systemd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+func TestParseHexEndpoint(t *testing.T) {
+	tests := []struct {
+		in      string
+		ip      string
+		port    int
+		wantErr bool
+	}{
+		{"0100007F:1F90", "127.0.0.1", 8080, false},
+		{"00000000:0016", "0.0.0.0", 22, false},
+		{"0101A8C0:C350", "192.168.1.1", 50000, false},
+		{"00000000000000000000000001000000:0277", "::1", 631, false},
+		{"00000000000000000000000000000000:0050", "::", 80, false},
+		{"0000000000000000FFFF00000100007F:0035", "127.0.0.1", 53, false},
+		{"B80D0120000000000000000001000000:01BB", "2001:db8::1", 443, false},
+		{"0100007F", "", 0, true},
+		{"0100007:1F90", "", 0, true},
+		{"0100007G:1F90", "", 0, true},
+		{"0100007F00:1F90", "", 0, true},
+		{"0100007F:10000", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		ip, port, err := parseHexEndpoint(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHexEndpoint(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if ip.String() != tt.ip || port != tt.port {
+			t.Errorf("parseHexEndpoint(%q) = %s, %d, want %s, %d", tt.in, ip, port, tt.ip, tt.port)
+		}
+	}
+}
+
+// writeProcFile writes a /proc/net table to a temporary file
+func writeProcFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadInetTable(t *testing.T) {
+	tcp := writeProcFile(t, "tcp", `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:1F90 0100007F:D432 06 00000000:00000000 03:00000DA9 00000000     0        0 0 3 0000000000000000
+   3: malformed
+`)
+	udp := writeProcFile(t, "udp6", `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  0: 00000000000000000000000000000000:0202 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 2001 2 0000000000000000 0
+`)
+
+	table := map[uint64]types.Socket{}
+	readInetTable(tcp, "tcp", table)
+	readInetTable(udp, "udp6", table)
+	readInetTable(filepath.Join(t.TempDir(), "missing"), "tcp6", table)
+
+	want := map[uint64]types.Socket{
+		1001: {Protocol: "tcp", Local: "0.0.0.0:8080", LocalPort: 8080, State: "LISTEN", Listening: true, Inode: 1001},
+		1002: {Protocol: "tcp", Local: "127.0.0.1:8080", Remote: "127.0.0.1:54321", LocalPort: 8080,
+			State: "ESTABLISHED", Inode: 1002},
+		2001: {Protocol: "udp6", Local: "[::]:514", LocalPort: 514, State: "UNCONN", Listening: true, Inode: 2001},
+	}
+	if len(table) != len(want) {
+		t.Fatalf("got %d sockets, want %d: %+v", len(table), len(want), table)
+	}
+	for inode, w := range want {
+		if got := table[inode]; got != w {
+			t.Errorf("inode %d: got %+v, want %+v", inode, got, w)
+		}
+	}
+}
+
+func TestReadUnixTable(t *testing.T) {
+	unix := writeProcFile(t, "unix", `Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 3001 /run/example.sock
+0000000000000000: 00000003 00000000 00000000 0001 03 3002 /run/example.sock
+0000000000000000: 00000002 00000000 00000000 0002 01 3003
+`)
+
+	table := map[uint64]types.Socket{}
+	readUnixTable(unix, table)
+
+	tests := []struct {
+		inode     uint64
+		local     string
+		state     string
+		listening bool
+	}{
+		{3001, "/run/example.sock", "LISTEN", true},
+		{3002, "/run/example.sock", "CONNECTED", false},
+		{3003, "", "UNCONN", false},
+	}
+	if len(table) != len(tests) {
+		t.Fatalf("got %d sockets, want %d: %+v", len(table), len(tests), table)
+	}
+	for _, tt := range tests {
+		s := table[tt.inode]
+		if s.Protocol != "unix" || s.Local != tt.local || s.State != tt.state || s.Listening != tt.listening {
+			t.Errorf("inode %d: got %+v, want %s %s listening %v", tt.inode, s, tt.local, tt.state, tt.listening)
+		}
+	}
+}
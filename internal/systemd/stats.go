@@ -0,0 +1,123 @@
+package systemd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"sdtop/internal/types"
+
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// topMessageCount is how many repeated messages GetLogStats reports
+const topMessageCount = 10
+
+// Patterns replaced when grouping repeated messages, most specific first
+var messageNormalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]*\d[0-9a-f]*[a-f][0-9a-f]*\b|\b[0-9a-f]*[a-f][0-9a-f]*\d[0-9a-f]*\b`), "<id>"},
+	{regexp.MustCompile(`\d+`), "#"},
+}
+
+// NormalizeMessage strips numbers, IDs and addresses from a message so
+// repeats of the same event group together
+func NormalizeMessage(msg string) string {
+	for _, n := range messageNormalizers {
+		msg = n.pattern.ReplaceAllString(msg, n.replacement)
+	}
+	return msg
+}
+
+// GetLogStats counts the entries matching a query per bucket from since
+// until now, and groups repeated messages. Only the timestamp, priority
+// and message of each entry are read.
+func (lr *LogReader) GetLogStats(q LogQuery, since time.Time, bucket time.Duration) (*types.LogStats, error) {
+	now := time.Now()
+	buckets := int(now.Sub(since)/bucket) + 1
+
+	stats := &types.LogStats{
+		Start:    since,
+		Bucket:   bucket,
+		Lines:    make([]int, buckets),
+		Errors:   make([]int, buckets),
+		Warnings: make([]int, buckets),
+	}
+	if len(q.Units) == 0 {
+		return stats, nil
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if err := lr.applyMatches(q); err != nil {
+		return nil, err
+	}
+	if err := lr.journal.SeekRealtimeUsec(uint64(since.UnixMicro())); err != nil {
+		return nil, fmt.Errorf("failed to seek to %s: %w", since, err)
+	}
+
+	messages := map[string]*types.MessageCount{}
+	for {
+		n, err := lr.journal.Next()
+		if err != nil || n == 0 {
+			break
+		}
+
+		usec, err := lr.journal.GetRealtimeUsec()
+		if err != nil {
+			continue
+		}
+		ts := usecToTime(usec)
+		i := int(ts.Sub(since) / bucket)
+		if i < 0 || i >= buckets {
+			continue
+		}
+
+		value, _ := lr.journal.GetDataValue(sdjournal.SD_JOURNAL_FIELD_PRIORITY)
+		priority, ok := types.ParsePriority(value)
+		if !ok {
+			priority = types.PriorityInfo
+		}
+
+		stats.Lines[i]++
+		stats.Total++
+		switch {
+		case priority <= types.PriorityErr:
+			stats.Errors[i]++
+			stats.TotalErrors++
+		case priority == types.PriorityWarning:
+			stats.Warnings[i]++
+			stats.TotalWarnings++
+		}
+
+		msg, _ := lr.journal.GetDataValue(sdjournal.SD_JOURNAL_FIELD_MESSAGE)
+		pattern := NormalizeMessage(msg)
+		count, ok := messages[pattern]
+		if !ok {
+			count = &types.MessageCount{Pattern: pattern, Priority: priority}
+			messages[pattern] = count
+		}
+		count.Count++
+		count.Priority = min(count.Priority, priority)
+		count.LastSeen = ts
+	}
+
+	for _, count := range messages {
+		stats.TopMessages = append(stats.TopMessages, *count)
+	}
+	sort.Slice(stats.TopMessages, func(i, j int) bool {
+		return stats.TopMessages[i].Count > stats.TopMessages[j].Count
+	})
+	if len(stats.TopMessages) > topMessageCount {
+		stats.TopMessages = stats.TopMessages[:topMessageCount]
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,33 @@
+package systemd
+
+import "testing"
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"Started session 42 of user alice.", "Started session # of user alice."},
+		{"Connection from 192.168.1.10:52344 closed", "Connection from <ip> closed"},
+		{"Accepted key for 10.0.0.1 port 22", "Accepted key for <ip> port #"},
+		{"request 3f2a9c1e-8b4d-4c2f-9a1e-7d6b5c4a3f2e failed", "request <uuid> failed"},
+		{"fault at 0x7ffd5e8c in worker", "fault at <hex> in worker"},
+		{"container 4fa8c2d91b07 exited", "container <id> exited"},
+		{"cache hit ratio 98.5%", "cache hit ratio #.#%"},
+		{"no numbers here", "no numbers here"},
+		{"deadbeef is a word, face too", "deadbeef is a word, face too"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeMessage(tt.msg); got != tt.want {
+			t.Errorf("NormalizeMessage(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+
+	// Repeats of the same event group together
+	a := NormalizeMessage("Worker 12 took 350ms for job 9f8e7d6c")
+	b := NormalizeMessage("Worker 3 took 1200ms for job 1a2b3c4d")
+	if a != b {
+		t.Errorf("%q and %q don't group together", a, b)
+	}
+}
@@ -0,0 +1,91 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+)
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc, which is 100 on
+// every Linux architecture
+const clockTicks = 100
+
+// cpuSample is a thread's CPU time at a point in time, to compute its CPU
+// usage at the next sample
+type cpuSample struct {
+	cpu time.Duration
+	at  time.Time
+}
+
+// getThreads reads the threads of a process from /proc/<pid>/task, with
+// their CPU usage since the samples in prev. samples collects this call's
+// CPU times for the next one.
+func getThreads(pid int, now time.Time, prev, samples map[int]cpuSample) []types.Thread {
+	taskDir := fmt.Sprintf("/proc/%d/task", pid)
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		return nil
+	}
+
+	threads := make([]types.Thread, 0, len(entries))
+	for _, entry := range entries {
+		tid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		thread, err := readThreadStat(fmt.Sprintf("%s/%d/stat", taskDir, tid))
+		if err != nil {
+			continue
+		}
+		thread.TID = tid
+
+		thread.CPUPercent = -1
+		if last, ok := prev[tid]; ok && now.After(last.at) {
+			thread.CPUPercent = float64(thread.CPUTime-last.cpu) / float64(now.Sub(last.at)) * 100
+		}
+		samples[tid] = cpuSample{cpu: thread.CPUTime, at: now}
+
+		threads = append(threads, thread)
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].CPUPercent != threads[j].CPUPercent {
+			return threads[i].CPUPercent > threads[j].CPUPercent
+		}
+		return threads[i].CPUTime > threads[j].CPUTime
+	})
+	return threads
+}
+
+// readThreadStat parses a thread's stat file: TID (NAME) STATE PPID ...,
+// with utime and stime as the 14th and 15th fields
+func readThreadStat(path string) (types.Thread, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.Thread{}, err
+	}
+
+	stat := string(data)
+	start, end := strings.Index(stat, "("), strings.LastIndex(stat, ")")
+	if start < 0 || end < start {
+		return types.Thread{}, fmt.Errorf("malformed %s", path)
+	}
+	// Fields after the name start with the 3rd, the state
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return types.Thread{}, fmt.Errorf("malformed %s", path)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+
+	return types.Thread{
+		Name:    stat[start+1 : end],
+		State:   fields[0],
+		CPUTime: time.Duration(utime+stime) * time.Second / clockTicks,
+	}, nil
+}
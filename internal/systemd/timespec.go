@@ -0,0 +1,129 @@
+package systemd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// absoluteLayouts are the absolute time formats accepted by ParseTimeSpec
+var absoluteLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// clockLayouts are times of day, interpreted as today
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+}
+
+// timeUnits maps relative time units to durations
+var timeUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// ParseTimeSpec parses a time the way journalctl --since/--until does:
+// absolute ("2024-05-01 13:00", "13:00"), keywords ("now", "today",
+// "yesterday") or relative ("-1h", "+30m", "2 days ago"). A bare
+// duration like "1h" means that long ago.
+func ParseTimeSpec(spec string, now time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch spec {
+	case "now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, spec, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, spec, now.Location()); err == nil {
+			return midnight.Add(time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second), nil
+		}
+	}
+
+	// Relative time
+	sign := time.Duration(-1)
+	rel := spec
+	switch {
+	case strings.HasSuffix(rel, " ago"):
+		rel = strings.TrimSuffix(rel, " ago")
+	case strings.HasPrefix(rel, "-"):
+		rel = rel[1:]
+	case strings.HasPrefix(rel, "+"):
+		rel = rel[1:]
+		sign = 1
+	}
+
+	d, err := parseRelative(rel)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", spec)
+	}
+
+	return now.Add(sign * d), nil
+}
+
+// parseRelative parses durations like "1h30m", "2 days" or "90 min"
+func parseRelative(s string) (time.Duration, error) {
+	var total time.Duration
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	for s != "" {
+		// Number
+		i := 0
+		for i < len(s) && unicode.IsDigit(rune(s[i])) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("expected number in %q", s)
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, err
+		}
+		s = strings.TrimLeft(s[i:], " ")
+
+		// Unit
+		j := 0
+		for j < len(s) && unicode.IsLetter(rune(s[j])) {
+			j++
+		}
+		unit, ok := timeUnits[s[:j]]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", s[:j])
+		}
+		total += time.Duration(n) * unit
+		s = strings.TrimLeft(s[j:], " ")
+	}
+
+	return total, nil
+}
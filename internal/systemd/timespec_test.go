@@ -0,0 +1,53 @@
+package systemd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpec(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 30, 45, 0, time.Local)
+	at := func(day, hour, min, sec int) time.Time {
+		return time.Date(2024, 5, day, hour, min, sec, 0, time.Local)
+	}
+
+	tests := []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"now", now, false},
+		{"  now  ", now, false},
+		{"today", at(10, 0, 0, 0), false},
+		{"yesterday", at(9, 0, 0, 0), false},
+		{"tomorrow", at(11, 0, 0, 0), false},
+		{"2024-05-01 13:00", at(1, 13, 0, 0), false},
+		{"2024-05-01 13:00:05", at(1, 13, 0, 5), false},
+		{"2024-05-01", at(1, 0, 0, 0), false},
+		{"13:00", at(10, 13, 0, 0), false},
+		{"08:15:30", at(10, 8, 15, 30), false},
+		{"-1h", now.Add(-time.Hour), false},
+		{"1h", now.Add(-time.Hour), false},
+		{"+30m", now.Add(30 * time.Minute), false},
+		{"2 days ago", now.Add(-48 * time.Hour), false},
+		{"1h30m", now.Add(-90 * time.Minute), false},
+		{"90 min ago", now.Add(-90 * time.Minute), false},
+		{"1 week", now.Add(-7 * 24 * time.Hour), false},
+		{"", time.Time{}, true},
+		{"soon", time.Time{}, true},
+		{"5 fortnights", time.Time{}, true},
+		{"-", time.Time{}, true},
+		{"25:00", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimeSpec(tt.spec, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeSpec(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeSpec(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
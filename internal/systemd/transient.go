@@ -0,0 +1,271 @@
+package systemd
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// TransientOptions describes an ad-hoc command to run as a transient unit,
+// like systemd-run
+type TransientOptions struct {
+	Name             string   // Unit name; generated when empty
+	Command          []string // Program and arguments
+	WorkingDirectory string
+	Environment      []string          // KEY=VALUE assignments
+	User             string            // Run as this user instead of root
+	Limits           map[string]uint64 // Keyed by directive, see ResourceLimits
+	Scope            bool              // Run as a scope instead of a service
+}
+
+// transientTimeout bounds the wait for systemd to start a transient unit
+const transientTimeout = 30 * time.Second
+
+// scopeLauncher holds a scope's command until it has been moved into the
+// scope, then runs it under systemd-cat so its output is journaled with
+// the scope's unit rather than sdtop's
+const scopeLauncher = `read -r _; exec systemd-cat --identifier="$0" -- "$@"`
+
+// SplitCommand splits a command line into words the way a shell would for
+// quoting: single quotes are literal, double quotes allow \" and \\, and a
+// backslash escapes the next character outside quotes
+func SplitCommand(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+				word.WriteRune(runes[i])
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// TransientUnitName completes a unit name with the .service or .scope
+// suffix, generating a random one like systemd-run when it's empty
+func TransientUnitName(name string, scope bool) (string, error) {
+	suffix := ".service"
+	if scope {
+		suffix = ".scope"
+	}
+	if name == "" {
+		name = fmt.Sprintf("run-sdtop-%08x", rand.Uint32())
+	}
+	if strings.HasSuffix(name, ".service") || strings.HasSuffix(name, ".scope") {
+		if !strings.HasSuffix(name, suffix) {
+			return "", fmt.Errorf("%s doesn't match the unit type %s", name, suffix)
+		}
+		name = strings.TrimSuffix(name, suffix)
+	}
+
+	for _, r := range name {
+		if !strings.ContainsRune(`:-_.\`, r) && (r > 127 || !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')) {
+			return "", fmt.Errorf("unit names can't contain %q", r)
+		}
+	}
+	return name + suffix, nil
+}
+
+// IsTransientRun reports whether a unit was started by systemd-run or sdtop
+// without a unit name, which is how other tools' scopes make it into the
+// service list
+func IsTransientRun(name string) bool {
+	return strings.HasPrefix(name, "run-") && strings.HasSuffix(name, ".scope")
+}
+
+// StartTransient runs a command as a transient unit and waits for systemd
+// to start it, returning the unit name. A service is started by systemd
+// itself and stays loaded after its command exits, like systemd-run
+// --remain-after-exit, so its result and logs can still be looked at; a
+// scope wraps a process sdtop starts. Either is listed with the services
+// while it's loaded.
+func (m *Manager) StartTransient(opts TransientOptions) (string, error) {
+	if len(opts.Command) == 0 {
+		return "", fmt.Errorf("no command given")
+	}
+	name, err := TransientUnitName(opts.Name, opts.Scope)
+	if err != nil {
+		return "", err
+	}
+	for _, env := range opts.Environment {
+		if key, _, ok := strings.Cut(env, "="); !ok || key == "" {
+			return "", fmt.Errorf("environment %q is not a KEY=VALUE assignment", env)
+		}
+	}
+
+	path, err := resolveCommand(opts.Command[0], opts.WorkingDirectory)
+	if err != nil {
+		return "", err
+	}
+	argv := append([]string{path}, opts.Command[1:]...)
+
+	props := []dbus.Property{dbus.PropDescription(strings.Join(opts.Command, " "))}
+	props = append(props, limitProperties(opts.Limits)...)
+
+	if opts.Scope {
+		err = m.startScope(name, argv, opts, props)
+	} else {
+		err = m.startService(name, argv, opts, props)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.launched[name] = true
+	m.mu.Unlock()
+	return name, nil
+}
+
+// resolveCommand finds the absolute path systemd needs to run a command,
+// the way systemd-run does: a name is looked up in $PATH and a relative
+// path is taken from the working directory the command runs in
+func resolveCommand(command, dir string) (string, error) {
+	if strings.Contains(command, "/") && !filepath.IsAbs(command) && dir != "" {
+		command = filepath.Join(dir, command)
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("%s is not an absolute path; set a working directory or give the full path", path)
+	}
+	return path, nil
+}
+
+// startService starts a transient service running the command
+func (m *Manager) startService(name string, argv []string, opts TransientOptions, props []dbus.Property) error {
+	props = append(props, dbus.PropExecStart(argv, true), dbus.PropRemainAfterExit(true))
+	if opts.WorkingDirectory != "" {
+		props = append(props, stringProperty("WorkingDirectory", opts.WorkingDirectory))
+	}
+	if len(opts.Environment) > 0 {
+		props = append(props, dbus.Property{Name: "Environment", Value: godbus.MakeVariant(opts.Environment)})
+	}
+	if opts.User != "" {
+		props = append(props, stringProperty("User", opts.User))
+	}
+	return m.startTransientUnit(name, props)
+}
+
+// startScope starts the command held by scopeLauncher, moves it into a new
+// scope and then lets it run
+func (m *Manager) startScope(name string, argv []string, opts TransientOptions, props []dbus.Property) error {
+	cmd := exec.Command("sh", append([]string{"-c", scopeLauncher, filepath.Base(argv[0])}, argv...)...)
+	cmd.Dir = opts.WorkingDirectory
+	cmd.Env = append(os.Environ(), opts.Environment...)
+	if opts.User != "" {
+		cred, err := userCredential(opts.User)
+		if err != nil {
+			return err
+		}
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: cred}
+	}
+	release, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the process whenever it exits; its output goes to the journal
+	go cmd.Wait()
+
+	props = append(props, dbus.PropPids(uint32(cmd.Process.Pid)))
+	if err := m.startTransientUnit(name, props); err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+	release.Write([]byte("\n"))
+	return release.Close()
+}
+
+// startTransientUnit creates a transient unit and waits for its start job
+func (m *Manager) startTransientUnit(name string, props []dbus.Property) error {
+	done := make(chan string, 1)
+	if _, err := m.conn.StartTransientUnit(name, "fail", props, done); err != nil {
+		return err
+	}
+
+	select {
+	case result := <-done:
+		if result != "done" {
+			return fmt.Errorf("starting %s: job %s", name, result)
+		}
+		return nil
+	case <-time.After(transientTimeout):
+		return fmt.Errorf("starting %s: timed out after %s", name, transientTimeout)
+	}
+}
+
+// userCredential looks up the user and primary group to run a scope as
+func userCredential(name string) (*syscall.Credential, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// stringProperty builds a unit property holding a string
+func stringProperty(name, value string) dbus.Property {
+	return dbus.Property{Name: name, Value: godbus.MakeVariant(value)}
+}
@@ -0,0 +1,129 @@
+package systemd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"   ", nil, false},
+		{"sleep 60", []string{"sleep", "60"}, false},
+		{"  ls\t-l   /tmp ", []string{"ls", "-l", "/tmp"}, false},
+		{`echo 'single $quoted \n'`, []string{"echo", `single $quoted \n`}, false},
+		{`echo "say \"hi\" \\ \n"`, []string{"echo", `say "hi" \ \n`}, false},
+		{`echo a\ b \'c`, []string{"echo", "a b", "'c"}, false},
+		{`printf ''`, []string{"printf", ""}, false},
+		{`KEY="a b"c`, []string{"KEY=a bc"}, false},
+		{`echo 'unterminated`, nil, true},
+		{`echo "unterminated`, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SplitCommand(%q) error = %v, want error %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTransientUnitName(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   bool
+		want    string
+		wantErr bool
+	}{
+		{"backup", false, "backup.service", false},
+		{"backup", true, "backup.scope", false},
+		{"backup.service", false, "backup.service", false},
+		{"backup.scope", true, "backup.scope", false},
+		{"backup.scope", false, "", true},
+		{`dev-disk-by\x2dlabel`, false, `dev-disk-by\x2dlabel.service`, false},
+		{"job:2_3.x", false, "job:2_3.x.service", false},
+		{"job;1", false, "", true},
+		{"two words", false, "", true},
+		{"ünicode", true, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := TransientUnitName(tt.name, tt.scope)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("TransientUnitName(%q, %v) error = %v, want error %v", tt.name, tt.scope, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("TransientUnitName(%q, %v) = %q, want %q", tt.name, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestTransientUnitNameGenerated(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		name, err := TransientUnitName("", i%2 == 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(name, "run-sdtop-") {
+			t.Errorf("generated %q, want a run-sdtop- prefix", name)
+		}
+		if seen[name] {
+			t.Errorf("generated %q twice", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "bin", "tool")
+	if err := os.MkdirAll(filepath.Dir(tool), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{"bin/tool", dir, tool, false},
+		{"./bin/tool", dir, tool, false},
+		{tool, "", tool, false},
+		{tool, "/nonexistent", tool, false},
+		{"bin/tool", "", "", true},
+		{"bin/missing", dir, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveCommand(tt.command, tt.dir)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveCommand(%q, %q) error = %v, want error %v", tt.command, tt.dir, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveCommand(%q, %q) = %q, want %q", tt.command, tt.dir, got, tt.want)
+		}
+	}
+
+	// Names are looked up in $PATH
+	if got, err := resolveCommand("sh", dir); err != nil || !filepath.IsAbs(got) {
+		t.Errorf("resolveCommand(sh) = %q, %v", got, err)
+	}
+}
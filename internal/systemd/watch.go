@@ -0,0 +1,78 @@
+package systemd
+
+import (
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// stateBuffer is how many unit property updates may queue up before
+// systemd's signals are dropped
+const stateBuffer = 256
+
+// WatchServiceStates subscribes to systemd's PropertiesChanged signals and
+// reports every ActiveState change of a service as it happens
+func (m *Manager) WatchServiceStates() (<-chan types.StateChange, error) {
+	// PropertiesChanged carries ActiveState on every emission, even when
+	// only a job or timestamp changed, so changes are found by comparing
+	// with the last known state
+	units, err := m.conn.ListUnits()
+	if err != nil {
+		return nil, err
+	}
+	last := make(map[string]string, len(units))
+	for _, unit := range units {
+		last[unit.Name] = unit.ActiveState
+	}
+
+	if err := m.conn.Subscribe(); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *dbus.PropertiesUpdate, stateBuffer)
+	errs := make(chan error, 1)
+	m.conn.SetPropertiesSubscriber(updates, errs)
+
+	changes := make(chan types.StateChange, stateBuffer)
+	go func() {
+		defer close(changes)
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if !strings.HasSuffix(update.UnitName, ".service") {
+					continue
+				}
+				active, ok := update.Changed["ActiveState"]
+				if !ok {
+					continue
+				}
+
+				change := types.StateChange{
+					Unit: update.UnitName,
+					Time: time.Now(),
+				}
+				change.ActiveState, _ = active.Value().(string)
+				if last[change.Unit] == change.ActiveState {
+					continue
+				}
+				last[change.Unit] = change.ActiveState
+				if sub, ok := update.Changed["SubState"]; ok {
+					change.SubState, _ = sub.Value().(string)
+				}
+				changes <- change
+
+			case <-errs:
+				// A full update channel only means some changes were
+				// dropped; keep watching
+			}
+		}
+	}()
+
+	return changes, nil
+}
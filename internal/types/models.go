@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Service represents a systemd service unit
 type Service struct {
@@ -12,11 +15,147 @@ type Service struct {
 	UnitFileState string // enabled, disabled, static, masked
 }
 
+// Priority is a syslog priority level as stored by journald
+type Priority int
+
+// Syslog priority levels, from most to least severe
+const (
+	PriorityEmerg Priority = iota
+	PriorityAlert
+	PriorityCrit
+	PriorityErr
+	PriorityWarning
+	PriorityNotice
+	PriorityInfo
+	PriorityDebug
+)
+
+var priorityNames = [...]string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// String returns the syslog name of the priority
+func (p Priority) String() string {
+	if p < PriorityEmerg || p > PriorityDebug {
+		return strconv.Itoa(int(p))
+	}
+	return priorityNames[p]
+}
+
+// ParsePriority parses a syslog priority name ("warning") or number ("4")
+func ParsePriority(s string) (Priority, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < int(PriorityEmerg) || n > int(PriorityDebug) {
+			return 0, false
+		}
+		return Priority(n), true
+	}
+
+	// Accept the aliases journalctl understands
+	switch s {
+	case "error":
+		return PriorityErr, true
+	case "warn":
+		return PriorityWarning, true
+	case "panic":
+		return PriorityEmerg, true
+	}
+
+	for i, name := range priorityNames {
+		if s == name {
+			return Priority(i), true
+		}
+	}
+	return 0, false
+}
+
+// LogSource tells who wrote a log entry about a unit
+type LogSource int
+
+const (
+	SourceUnit     LogSource = iota // The unit's own processes
+	SourceManager                   // systemd (PID 1) reporting on the unit
+	SourceCoredump                  // systemd-coredump reporting a crash
+)
+
 // LogEntry represents a single journald log entry
 type LogEntry struct {
 	Timestamp time.Time
 	Message   string
-	Priority  string
+	Priority  Priority
+	Cursor    string    // Journal position, used to page through history
+	Unit      string    // Unit the entry belongs to
+	Source    LogSource // Who wrote the entry
+
+	// Fields holds every journal field of the entry (MESSAGE, _PID,
+	// _COMM, SYSLOG_IDENTIFIER, ...), including the __CURSOR,
+	// __REALTIME_TIMESTAMP and __MONOTONIC_TIMESTAMP address fields
+	Fields map[string]string
+}
+
+// Coredump describes a crash recorded by systemd-coredump
+type Coredump struct {
+	Time    time.Time
+	Unit    string // Unit the crashed process belonged to
+	PID     int
+	Signal  int
+	Exe     string
+	Comm    string
+	Message string // Summary with the stack trace, if one was captured
+	Cursor  string // Journal position of the crash report
+}
+
+// StateChange records a unit moving to a new active state
+type StateChange struct {
+	Unit        string
+	ActiveState string // active, activating, deactivating, inactive, failed
+	SubState    string
+	Time        time.Time
+}
+
+// FailedUnit describes a unit in the failed state for triage
+type FailedUnit struct {
+	Name        string
+	Description string
+	Result      string    // Why it failed (exit-code, signal, timeout, ...)
+	ExitCode    int32     // How the main process ended (CLD_EXITED, CLD_KILLED, CLD_DUMPED)
+	ExitStatus  int32     // Exit status or signal number of the main process
+	FailedAt    time.Time // When the unit entered the failed state
+	NRestarts   uint32    // Automatic restarts since the unit was started
+
+	// LastErrors holds the most recent error-priority log entries
+	LastErrors []LogEntry
+}
+
+// LogStats summarizes log activity over a time window in fixed buckets
+type LogStats struct {
+	Start    time.Time
+	Bucket   time.Duration
+	Lines    []int // Entries per bucket
+	Errors   []int // Entries at err or more severe per bucket
+	Warnings []int // Entries at warning per bucket
+
+	Total         int
+	TotalErrors   int
+	TotalWarnings int
+
+	// TopMessages are the most repeated messages after normalization,
+	// most frequent first
+	TopMessages []MessageCount
+}
+
+// MessageCount counts occurrences of a normalized message
+type MessageCount struct {
+	Pattern  string // Message with numbers and IDs replaced
+	Count    int
+	Priority Priority // Most severe level seen
+	LastSeen time.Time
+}
+
+// Boot describes one boot recorded in the journal
+type Boot struct {
+	ID     string
+	Offset int // 0 is the current boot, -1 the previous one, and so on
+	First  time.Time
+	Last   time.Time
 }
 
 // Process represents a running process
@@ -26,4 +165,183 @@ type Process struct {
 	Cmdline  string
 	Parent   int
 	Children []*Process
+	// NumThreads counts the threads; Threads lists them, busiest first,
+	// only for the processes they were asked for
+	NumThreads int
+	Threads    []Thread
+}
+
+// Thread is a thread of a process
+type Thread struct {
+	TID        int
+	Name       string
+	State      string        // R running, S sleeping, D disk sleep, ...
+	CPUTime    time.Duration // User and system time since it started
+	CPUPercent float64       // Of one CPU since the previous sample, -1 when unknown
+}
+
+// Dependency is a unit related to another one, as listed by
+// systemctl list-dependencies
+type Dependency struct {
+	Unit        string
+	Relation    string // Property linking the units, e.g. Requires or WantedBy
+	ActiveState string
+	SubState    string
+}
+
+// UnitGraph is a dependency and ordering graph between units
+type UnitGraph struct {
+	Root   string            // Unit the graph starts from, empty for the whole system
+	States map[string]string // Active state of each unit in the graph
+	Edges  []UnitEdge
+}
+
+// UnitEdge links two units through a dependency or ordering property,
+// e.g. From Requires To
+type UnitEdge struct {
+	From     string
+	To       string
+	Relation string
+}
+
+// UnitTiming is when a unit started during boot, as offsets from kernel
+// start (CLOCK_MONOTONIC)
+type UnitTiming struct {
+	Unit       string
+	Activating time.Duration // Left the inactive state
+	Activated  time.Duration // Became active
+	Time       time.Duration // Activated - Activating, zero when instant
+}
+
+// BootAnalysis breaks down how long the system took to boot, like
+// systemd-analyze
+type BootAnalysis struct {
+	Firmware  time.Duration // Zero when the boot loader doesn't report it
+	Loader    time.Duration
+	Kernel    time.Duration
+	InitRD    time.Duration // Zero without an initrd
+	Userspace time.Duration
+	Finished  time.Duration // Offset at which startup finished, from kernel start
+
+	// Units that started during boot, in startup order
+	Units []UnitTiming
+
+	// DefaultTarget and its critical chain: the units whose activation
+	// it waited for, from the target back to the first one
+	DefaultTarget string
+	Chain         []UnitTiming
+}
+
+// SecurityCheck is one sandboxing setting evaluated for a service
+type SecurityCheck struct {
+	Name        string  // Setting checked, e.g. ProtectSystem
+	Description string  // What the current value means
+	Fix         string  // Directive that would pass the check
+	Weight      int     // How much the setting matters
+	Exposure    float64 // 0 when fully hardened up to 1 when not at all
+}
+
+// SecurityReport is a service's exposure, scored like
+// systemd-analyze security from 0 (hardened) to 10 (exposed)
+type SecurityReport struct {
+	Unit   string
+	Score  float64
+	Checks []SecurityCheck // Most exposing first
+}
+
+// LintSeverity is how serious a unit file finding is
+type LintSeverity int
+
+const (
+	LintError   LintSeverity = iota // systemd rejects or ignores the setting
+	LintWarning                     // Works, but likely not as intended
+	LintInfo                        // Worth knowing
+)
+
+// String returns the severity's name
+func (s LintSeverity) String() string {
+	switch s {
+	case LintError:
+		return "error"
+	case LintWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// LintFinding is a problem found in a unit file
+type LintFinding struct {
+	File     string
+	Line     int // 1-based, 0 when the finding is about the whole file
+	Severity LintSeverity
+	Message  string
+}
+
+// ExecCommand is a command line of a service (ExecStart=, ExecReload=, ...)
+// with how it last ran
+type ExecCommand struct {
+	Directive     string // ExecStart, ExecStartPre, ...
+	Path          string
+	Argv          []string
+	IgnoreFailure bool // Prefixed with "-"
+	StartedAt     time.Time
+	ExitedAt      time.Time
+	PID           uint32 // 0 when it never ran
+	ExitCode      int32  // CLD_EXITED, CLD_KILLED or CLD_DUMPED
+	ExitStatus    int32
+}
+
+// EnvironmentFile is an EnvironmentFile= setting with the variables read
+// from it
+type EnvironmentFile struct {
+	Path     string
+	Optional bool     // Prefixed with "-": missing files are ignored
+	Vars     []string // KEY=VALUE, in file order
+	Err      string   // Why the file couldn't be read
+}
+
+// EnvVar compares a variable's configured and actual value
+type EnvVar struct {
+	Key        string
+	Configured string
+	Source     string // Environment= or the file that set it
+	Runtime    string
+	InConfig   bool
+	InRuntime  bool
+}
+
+// ServiceEnvironment is what a service is configured to run and with which
+// environment, next to what its main process actually got
+type ServiceEnvironment struct {
+	Unit             string
+	User             string
+	Group            string
+	WorkingDirectory string
+	MainPID          uint32
+	Commands         []ExecCommand
+	Environment      []string // Environment= assignments
+	Files            []EnvironmentFile
+	Vars             []EnvVar // Configured and runtime variables side by side
+	RuntimeErr       string   // Why /proc/<MainPID>/environ couldn't be read
+}
+
+// Socket is an open socket of a process, from /proc/net
+type Socket struct {
+	Protocol  string // tcp, tcp6, udp, udp6 or unix
+	Local     string // address:port, or the path of a unix socket
+	Remote    string // Empty when not connected
+	LocalPort int
+	State     string // LISTEN, ESTABLISHED, ... as in ss(8)
+	Listening bool   // Accepts connections, or a bound datagram socket
+	Inode     uint64
+	PID       int
+	Process   string // Process name
+	Unit      string // Unit the process belongs to
+}
+
+// SocketReport lists the sockets of a set of processes
+type SocketReport struct {
+	Sockets    []Socket
+	Unreadable int // Processes whose file descriptors couldn't be read
 }
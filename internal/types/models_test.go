@@ -0,0 +1,45 @@
+package types
+
+import "testing"
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Priority
+		wantOK bool
+	}{
+		{"0", PriorityEmerg, true},
+		{"4", PriorityWarning, true},
+		{"7", PriorityDebug, true},
+		{"8", 0, false},
+		{"-1", 0, false},
+		{"emerg", PriorityEmerg, true},
+		{"err", PriorityErr, true},
+		{"warning", PriorityWarning, true},
+		{"debug", PriorityDebug, true},
+		{"error", PriorityErr, true},
+		{"warn", PriorityWarning, true},
+		{"panic", PriorityEmerg, true},
+		{"ERR", 0, false},
+		{"", 0, false},
+		{"verbose", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePriority(tt.in)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParsePriority(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPriorityString(t *testing.T) {
+	for p := PriorityEmerg; p <= PriorityDebug; p++ {
+		if got, ok := ParsePriority(p.String()); !ok || got != p {
+			t.Errorf("ParsePriority(%q) = %v, %v, want %v", p.String(), got, ok, p)
+		}
+	}
+	if got := Priority(9).String(); got != "9" {
+		t.Errorf("Priority(9).String() = %q, want 9", got)
+	}
+}
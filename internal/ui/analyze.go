@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// analysisMode is a page of the boot analysis overlay
+type analysisMode int
+
+const (
+	analysisBlame analysisMode = iota
+	analysisChain
+	analysisGantt
+)
+
+// analysisView shows how long the boot took and which units slowed it
+type analysisView struct {
+	boot   *types.BootAnalysis // nil while loading
+	mode   analysisMode
+	offset int // First rendered line of the page
+}
+
+// analysisLoadedMsg carries the boot analysis
+type analysisLoadedMsg struct {
+	boot *types.BootAnalysis
+	err  error
+}
+
+// openAnalysis shows the boot analysis in place of the logs
+func (m *Model) openAnalysis() tea.Cmd {
+	m.analysis = &analysisView{}
+	return m.loadAnalysis()
+}
+
+// loadAnalysis reads boot and unit timestamps from systemd
+func (m *Model) loadAnalysis() tea.Cmd {
+	return func() tea.Msg {
+		boot, err := m.manager.AnalyzeBoot()
+		return analysisLoadedMsg{boot: boot, err: err}
+	}
+}
+
+// handleAnalysisLoaded shows a freshly loaded boot analysis
+func (m *Model) handleAnalysisLoaded(msg analysisLoadedMsg) {
+	if m.analysis == nil {
+		return
+	}
+	if msg.err != nil {
+		m.analysis = nil
+		m.errMsg = fmt.Sprintf("Failed to analyze boot: %v", msg.err)
+		return
+	}
+	m.analysis.boot = msg.boot
+	m.scrollAnalysis()
+}
+
+// updateAnalysis handles keys while the boot analysis is open
+func (m *Model) updateAnalysis(msg tea.KeyMsg) tea.Cmd {
+	a := m.analysis
+
+	switch msg.String() {
+	case "esc", "A", "q":
+		m.analysis = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "b":
+		a.mode, a.offset = analysisBlame, 0
+	case "c":
+		a.mode, a.offset = analysisChain, 0
+	case "g":
+		a.mode, a.offset = analysisGantt, 0
+	case "up", "k":
+		a.offset = max(0, a.offset-1)
+	case "down", "j":
+		a.offset++
+	case "pgup", "ctrl+u":
+		a.offset = max(0, a.offset-m.logViewport.Height/2)
+	case "pgdown", "ctrl+d":
+		a.offset += m.logViewport.Height / 2
+	case "R":
+		a.boot = nil
+		return m.loadAnalysis()
+	}
+	m.scrollAnalysis()
+	return nil
+}
+
+// analysisRows is how many lines of the page fit below the summary
+func (m *Model) analysisRows() int {
+	// The header, summary and a blank line take four lines
+	return max(1, m.logViewport.Height-2-4)
+}
+
+// analysisLines lays out the current page of the boot analysis
+func (m *Model) analysisLines() []string {
+	a := m.analysis
+	switch a.mode {
+	case analysisBlame:
+		return m.blameLines(a.boot)
+	case analysisChain:
+		return m.chainLines(a.boot)
+	default:
+		return m.ganttLines(a.boot, m.logViewport.Width-2)
+	}
+}
+
+// scrollAnalysis keeps the page scrolled within its lines
+func (m *Model) scrollAnalysis() {
+	a := m.analysis
+	if a == nil || a.boot == nil {
+		return
+	}
+	a.offset = max(0, min(a.offset, len(m.analysisLines())-m.analysisRows()))
+}
+
+// renderAnalysis draws the boot analysis overlay in place of the logs
+func (m *Model) renderAnalysis() string {
+	a := m.analysis
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	tabs := []string{"[b]lame", "[c]ritical chain", "[g]antt"}
+	for i, tab := range tabs {
+		if analysisMode(i) == a.mode {
+			tabs[i] = titleStyle.Render(tab)
+		} else {
+			tabs[i] = labelStyle.Render(tab)
+		}
+	}
+	header := titleStyle.Render("BOOT ANALYSIS  ") + strings.Join(tabs, labelStyle.Render(" • ")) +
+		labelStyle.Render("  [R]efresh • esc close")
+
+	if a.boot == nil {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Reading unit timestamps..."))
+	}
+
+	lines := m.analysisLines()
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+
+	end := min(len(lines), a.offset+m.analysisRows())
+
+	return m.statsFrame(header + "\n" + m.bootSummary(a.boot) + "\n\n" +
+		strings.Join(lines[min(a.offset, end):end], "\n"))
+}
+
+// bootSummary is systemd-analyze time's "Startup finished in ..." line
+func (m *Model) bootSummary(boot *types.BootAnalysis) string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	if boot.Finished == 0 {
+		return lipgloss.NewStyle().Foreground(m.theme.Warning).Render("Bootup is not yet finished")
+	}
+
+	var parts []string
+	total := time.Duration(0)
+	for _, part := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"firmware", boot.Firmware},
+		{"loader", boot.Loader},
+		{"kernel", boot.Kernel},
+		{"initrd", boot.InitRD},
+		{"userspace", boot.Userspace},
+	} {
+		if part.d > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%s)", formatSpan(part.d), part.name))
+			total += part.d
+		}
+	}
+
+	summary := labelStyle.Render("Startup finished in ") + strings.Join(parts, " + ") +
+		labelStyle.Render(" = ") + lipgloss.NewStyle().Bold(true).Render(formatSpan(total))
+	if len(boot.Chain) > 0 {
+		summary += labelStyle.Render(fmt.Sprintf(" · %s reached after %s",
+			boot.DefaultTarget, formatSpan(boot.Chain[0].Activated)))
+	}
+	return summary
+}
+
+// blameLines lists units by how long they took to start, slowest first
+func (m *Model) blameLines(boot *types.BootAnalysis) []string {
+	units := make([]types.UnitTiming, 0, len(boot.Units))
+	for _, t := range boot.Units {
+		if t.Time > 0 {
+			units = append(units, t)
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].Time > units[j].Time
+	})
+
+	lines := make([]string, len(units))
+	for i, t := range units {
+		lines[i] = m.timeStyle(t.Time).Render(fmt.Sprintf("%10s", formatSpan(t.Time))) + " " + t.Unit
+	}
+	if len(lines) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Muted).Render("No unit start times recorded"))
+	}
+	return lines
+}
+
+// chainLines draws the critical chain of the default target like
+// systemd-analyze critical-chain: when each unit became active (@) and
+// how long it took to start (+)
+func (m *Model) chainLines(boot *types.BootAnalysis) []string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	if len(boot.Chain) == 0 {
+		return []string{labelStyle.Render("No critical chain: the default target isn't active")}
+	}
+
+	// The chain is a tree where every unit has one child
+	children := func(i int) []int {
+		if i+1 < len(boot.Chain) {
+			return []int{i + 1}
+		}
+		return nil
+	}
+	line := func(i int, branch string) string {
+		t := boot.Chain[i]
+		text := labelStyle.Render(branch) + t.Unit + labelStyle.Render(" @"+formatSpan(t.Activated))
+		if t.Time > 0 {
+			text += " " + m.timeStyle(t.Time).Render("+"+formatSpan(t.Time))
+		}
+		return text
+	}
+
+	var sb strings.Builder
+	renderTree(&sb, 0, "", true, children, line)
+	return strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+}
+
+// ganttLines draws when each unit started and how long it took, in
+// startup order, on a time axis fitted to the pane
+func (m *Model) ganttLines(boot *types.BootAnalysis, width int) []string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	end := boot.Finished
+	for _, t := range boot.Units {
+		end = max(end, t.Activated)
+	}
+	nameWidth := min(32, width/3)
+	barWidth := max(10, width-nameWidth-1)
+	if end == 0 {
+		return nil
+	}
+	column := func(d time.Duration) int {
+		return min(barWidth-1, int(int64(d)*int64(barWidth)/int64(end)))
+	}
+
+	lines := []string{labelStyle.Render(fmt.Sprintf("%-*s %s%*s", nameWidth, "",
+		"0", barWidth-1, formatSpan(end)))}
+	for _, t := range boot.Units {
+		start := column(t.Activating)
+		if t.Activating == 0 {
+			start = column(t.Activated)
+		}
+		length := max(1, column(t.Activated)-start)
+
+		bar := strings.Repeat(" ", start) + m.timeStyle(t.Time).Render(strings.Repeat("█", length))
+		if t.Time > 0 && start+length+1+len(formatSpan(t.Time)) <= barWidth {
+			bar += labelStyle.Render(" " + formatSpan(t.Time))
+		}
+		lines = append(lines, fmt.Sprintf("%-*s ", nameWidth, truncate(t.Unit, nameWidth))+bar)
+	}
+	return lines
+}
+
+// timeStyle colors a start time by how much it slowed the boot
+func (m *Model) timeStyle(d time.Duration) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case d >= time.Second:
+		return style.Foreground(m.theme.Error)
+	case d >= 100*time.Millisecond:
+		return style.Foreground(m.theme.Warning)
+	default:
+		return style.Foreground(m.theme.Accent)
+	}
+}
+
+// formatSpan formats a duration like systemd-analyze: "532ms", "1.204s"
+// or "1min 2.5s"
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.3fs", d.Seconds())
+	default:
+		return fmt.Sprintf("%dmin %.1fs", int(d.Minutes()), (d % time.Minute).Seconds())
+	}
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// catalogLoadedMsg carries the catalog text of a log entry
+type catalogLoadedMsg struct {
+	cursor string
+	text   string
+}
+
+// syncCatalog loads the catalog explanation of the selected entry when it
+// carries a MESSAGE_ID, so the side panel follows the selection
+func (m *Model) syncCatalog() tea.Cmd {
+	idx := m.selectedLog()
+	if idx < 0 {
+		return nil
+	}
+
+	entry := m.logs.At(idx)
+	if entry.Fields["MESSAGE_ID"] == "" || entry.Cursor == m.catalogCursor {
+		return nil
+	}
+
+	m.catalogCursor = entry.Cursor
+	m.catalogText = ""
+	cursor := entry.Cursor
+	return func() tea.Msg {
+		// Most message IDs have no catalog entry; that just hides the panel
+		text, _ := m.logReader.GetCatalog(cursor)
+		return catalogLoadedMsg{cursor: cursor, text: strings.TrimSpace(text)}
+	}
+}
+
+// catalogVisible reports whether the catalog panel is shown next to the logs
+func (m *Model) catalogVisible() bool {
+	return m.showCatalog && m.focus == focusLogs && m.showingLogs() &&
+		m.catalogText != "" && m.catalogCursor == m.selectedCursor
+}
+
+// catalogWidth is the width of the catalog panel, border included
+func (m *Model) catalogWidth() int {
+	return max(30, m.logViewport.Width*2/5)
+}
+
+// renderCatalog draws the catalog explanation of the selected entry
+func (m *Model) renderCatalog(width, height int) string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Accent).
+		Render("ⓘ CATALOG") +
+		lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Render("  [c] hide")
+
+	text := lipgloss.NewStyle().
+		Foreground(m.theme.Text).
+		Width(width - 2).
+		Render(m.catalogText)
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(m.theme.Border).
+		PaddingLeft(1).
+		Width(width - 1).
+		Height(height).
+		MaxHeight(height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", text))
+}
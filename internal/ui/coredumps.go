@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Coredump browser limits
+const (
+	coredumpCount = 200 // Crash reports listed at most
+
+	// coredumpContext is how far past a crash the logs are shown when
+	// jumping to it, so the restart that followed is visible too
+	coredumpContext = time.Minute
+)
+
+// coredumpView lists crash reports of one unit or of all units
+type coredumpView struct {
+	unit   string // Unit whose crashes are listed, empty for all
+	dumps  []types.Coredump
+	cursor int
+	offset int // First rendered row, to keep the cursor on screen
+	trace  int // First rendered line of the selected crash's trace
+
+	loading bool
+}
+
+// coredumpsLoadedMsg carries crash reports
+type coredumpsLoadedMsg struct {
+	unit  string
+	dumps []types.Coredump
+	err   error
+}
+
+// openCoredumps shows the coredump browser, for the current service if
+// one is selected
+func (m *Model) openCoredumps() tea.Cmd {
+	m.coredumps = &coredumpView{unit: m.currentService, loading: true}
+	return m.loadCoredumps()
+}
+
+// loadCoredumps reads crash reports from the journal
+func (m *Model) loadCoredumps() tea.Cmd {
+	unit := m.coredumps.unit
+	return func() tea.Msg {
+		dumps, err := m.logReader.ListCoredumps(unit, coredumpCount)
+		return coredumpsLoadedMsg{unit: unit, dumps: dumps, err: err}
+	}
+}
+
+// handleCoredumpsLoaded shows freshly loaded crash reports
+func (m *Model) handleCoredumpsLoaded(msg coredumpsLoadedMsg) {
+	v := m.coredumps
+	if v == nil || msg.unit != v.unit {
+		return
+	}
+	v.loading = false
+
+	if msg.err != nil {
+		m.errMsg = fmt.Sprintf("Failed to list coredumps: %v", msg.err)
+		return
+	}
+	v.dumps = msg.dumps
+	v.cursor = min(v.cursor, max(0, len(v.dumps)-1))
+	m.scrollCoredumps()
+}
+
+// updateCoredumps handles keys in the coredump browser
+func (m *Model) updateCoredumps(msg tea.KeyMsg) tea.Cmd {
+	v := m.coredumps
+
+	switch msg.String() {
+	case "esc", "C", "q":
+		m.coredumps = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.cursor = max(0, v.cursor-1)
+		v.trace = 0
+	case "down", "j":
+		v.cursor = max(0, min(len(v.dumps)-1, v.cursor+1))
+		v.trace = 0
+	case "pgdown", "ctrl+d":
+		v.trace += 5
+	case "pgup", "ctrl+u":
+		v.trace = max(0, v.trace-5)
+	case "u":
+		// Switch between this unit's crashes and all crashes
+		if v.unit != "" {
+			v.unit = ""
+		} else {
+			v.unit = m.currentService
+		}
+		v.cursor, v.offset, v.trace = 0, 0, 0
+		v.loading = true
+		return m.loadCoredumps()
+	case "enter", "l":
+		return m.jumpToCoredump()
+	}
+	m.scrollCoredumps()
+	return nil
+}
+
+// coredumpRows is how many crashes the browser lists at once: up to a
+// third of the screen
+func (m *Model) coredumpRows() int {
+	return max(3, (m.height-4-2)/3)
+}
+
+// scrollCoredumps keeps the highlighted crash on screen and the trace
+// within the selected crash's report
+func (m *Model) scrollCoredumps() {
+	v := m.coredumps
+	if v == nil {
+		return
+	}
+
+	rows := m.coredumpRows()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	} else if v.cursor >= v.offset+rows {
+		v.offset = v.cursor - rows + 1
+	}
+
+	if v.cursor < len(v.dumps) {
+		lines := strings.Count(v.dumps[v.cursor].Message, "\n") + 1
+		v.trace = min(v.trace, max(0, lines-1))
+	}
+}
+
+// jumpToCoredump opens the logs of the crashed unit, ending shortly after
+// the crash
+func (m *Model) jumpToCoredump() tea.Cmd {
+	v := m.coredumps
+	if v.cursor >= len(v.dumps) {
+		return nil
+	}
+	dump := v.dumps[v.cursor]
+	if dump.Unit == "" {
+		return func() tea.Msg { return statusMsgType("This crash doesn't belong to a unit") }
+	}
+
+	m.coredumps = nil
+	m.restoreRange()
+	m.jumpToRange(time.Time{}, dump.Time.Add(coredumpContext))
+	m.focus = focusLogs
+	m.highlightService(dump.Unit)
+	return m.openServiceLogs(dump.Unit)
+}
+
+// renderCoredumps draws the coredump browser in place of both panes: the
+// crash list on top and the selected crash's report below
+func (m *Model) renderCoredumps() string {
+	v := m.coredumps
+	width := m.width - 4
+	height := m.height - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Error)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	gutter := lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▌")
+
+	scope := "all units"
+	if v.unit != "" {
+		scope = v.unit
+	}
+	header := titleStyle.Render(fmt.Sprintf("COREDUMPS · %s (%d)", scope, len(v.dumps))) +
+		mutedStyle.Render("  ↑↓ select • enter logs at crash • u this unit/all • pgup/pgdn trace • esc close")
+	if v.loading {
+		header += mutedStyle.Render("  loading...")
+	}
+
+	listHeight := m.coredumpRows()
+
+	var rows []string
+	for i := v.offset; i < min(len(v.dumps), v.offset+listHeight); i++ {
+		dump := v.dumps[i]
+		mark := " "
+		if i == v.cursor {
+			mark = gutter
+		}
+		row := fmt.Sprintf("%s  %-8s  %-28s  %7d  %s",
+			dump.Time.Format("2006-01-02 15:04:05"),
+			"SIG"+systemd.SignalName(int32(dump.Signal)),
+			truncate(orDash(dump.Unit), 28),
+			dump.PID,
+			dump.Exe,
+		)
+		rows = append(rows, mark+" "+truncate(row, max(10, width-2)))
+	}
+	if len(v.dumps) == 0 && !v.loading {
+		rows = append(rows, lipgloss.NewStyle().Foreground(m.theme.Success).Render("No coredumps recorded ✓"))
+	}
+	list := lipgloss.NewStyle().Height(listHeight).Render(strings.Join(rows, "\n"))
+
+	// The selected crash's report, with its stack trace
+	var trace string
+	if v.cursor < len(v.dumps) {
+		lines := strings.Split(v.dumps[v.cursor].Message, "\n")
+		start := min(v.trace, len(lines)-1)
+		end := min(len(lines), start+max(1, height-listHeight-4))
+		for i := range lines {
+			lines[i] = truncate(lines[i], max(10, width))
+		}
+		trace = strings.Join(lines[start:end], "\n")
+	}
+
+	separator := mutedStyle.Render(strings.Repeat("─", max(0, width)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Error).
+		Width(m.width - 2).
+		Height(height).
+		MaxHeight(height + 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", list, separator, trace))
+}
@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// depNode is a unit in the dependency tree. Children are loaded the first
+// time the node is expanded.
+type depNode struct {
+	dep      types.Dependency
+	parent   *depNode
+	children []*depNode
+	expanded bool
+	loaded   bool
+	loading  bool
+}
+
+// depView browses the dependencies of a unit as a tree
+type depView struct {
+	root    *depNode
+	reverse bool // Follow RequiredBy/WantedBy instead of Requires/Wants/...
+	cursor  int  // Index of the highlighted node among the visible ones
+	offset  int  // First rendered line, to keep the cursor on screen
+}
+
+// depsLoadedMsg carries the dependencies of a tree node
+type depsLoadedMsg struct {
+	view *depView
+	node *depNode
+	deps []types.Dependency
+	err  error
+}
+
+// openDeps shows the dependency tree of a unit with its first level
+// expanded
+func (m *Model) openDeps(root types.Dependency, reverse bool) tea.Cmd {
+	m.deps = &depView{root: &depNode{dep: root}, reverse: reverse}
+	return m.expandDep(m.deps.root)
+}
+
+// serviceDependency describes a listed service as the root of a tree
+func (m *Model) serviceDependency(name string) types.Dependency {
+	dep := types.Dependency{Unit: name}
+	for _, svc := range m.allServices {
+		if svc.Name == name {
+			dep.ActiveState, dep.SubState = svc.ActiveState, svc.SubState
+			break
+		}
+	}
+	return dep
+}
+
+// expandDep opens a node, loading its dependencies the first time
+func (m *Model) expandDep(node *depNode) tea.Cmd {
+	node.expanded = true
+	if node.loaded || node.loading || node.cyclic() {
+		return nil
+	}
+	node.loading = true
+
+	v := m.deps
+	return func() tea.Msg {
+		deps, err := m.manager.GetDependencies(node.dep.Unit, v.reverse)
+		return depsLoadedMsg{view: v, node: node, deps: deps, err: err}
+	}
+}
+
+// handleDepsLoaded attaches loaded dependencies to their node
+func (m *Model) handleDepsLoaded(msg depsLoadedMsg) {
+	if msg.view != m.deps {
+		return
+	}
+	node := msg.node
+	node.loading = false
+
+	if msg.err != nil {
+		node.expanded = false
+		m.errMsg = fmt.Sprintf("Failed to list dependencies of %s: %v", node.dep.Unit, msg.err)
+		return
+	}
+	node.loaded = true
+	node.children = make([]*depNode, len(msg.deps))
+	for i, dep := range msg.deps {
+		node.children[i] = &depNode{dep: dep, parent: node}
+	}
+}
+
+// cyclic reports whether the node's unit already appears among its
+// ancestors, in which case it isn't expanded again
+func (n *depNode) cyclic() bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p.dep.Unit == n.dep.Unit {
+			return true
+		}
+	}
+	return false
+}
+
+// depChildren returns the shown children of a tree node
+func depChildren(node *depNode) []*depNode {
+	if !node.expanded {
+		return nil
+	}
+	return node.children
+}
+
+// visible lists the nodes in the order the tree renders them
+func (v *depView) visible() []*depNode {
+	var flat []*depNode
+	var walk func(node *depNode)
+	walk = func(node *depNode) {
+		flat = append(flat, node)
+		for _, child := range depChildren(node) {
+			walk(child)
+		}
+	}
+	walk(v.root)
+	return flat
+}
+
+// selected returns the highlighted node
+func (v *depView) selected() *depNode {
+	flat := v.visible()
+	return flat[max(0, min(len(flat)-1, v.cursor))]
+}
+
+// updateDeps handles keys in the dependency view
+func (m *Model) updateDeps(msg tea.KeyMsg) tea.Cmd {
+	v := m.deps
+
+	switch msg.String() {
+	case "esc", "D", "q":
+		m.deps = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.cursor = max(0, v.cursor-1)
+	case "down", "j":
+		v.cursor = min(len(v.visible())-1, v.cursor+1)
+	case "right", " ":
+		return m.expandDep(v.selected())
+	case "left":
+		// Collapse the node, or move up to its parent
+		node := v.selected()
+		if node.expanded && len(node.children) > 0 {
+			node.expanded = false
+		} else if node.parent != nil {
+			for i, n := range v.visible() {
+				if n == node.parent {
+					v.cursor = i
+				}
+			}
+		}
+	case "R":
+		return m.openDeps(v.root.dep, !v.reverse)
+	case "G":
+		return m.promptGraph()
+	case "enter":
+		// Make the dependency the current unit and show its own tree
+		dep := v.selected().dep
+		cmd := m.selectService(dep.Unit)
+		return tea.Batch(cmd, m.openDeps(dep, v.reverse))
+	case "l":
+		// Show the highlighted unit's logs
+		unit := v.selected().dep.Unit
+		m.deps = nil
+		m.showProcessTree = false
+		m.focus = focusLogs
+		return m.selectService(unit)
+	}
+	m.scrollDeps()
+	return nil
+}
+
+// depRows is how many tree lines fit below the dependency view's header
+func (m *Model) depRows() int {
+	// The header and a blank line take two lines of the screen's height
+	return max(1, m.height-4-2)
+}
+
+// scrollDeps keeps the cursor on a shown node and that node on screen
+func (m *Model) scrollDeps() {
+	v := m.deps
+	if v == nil {
+		return
+	}
+
+	v.cursor = max(0, min(len(v.visible())-1, v.cursor))
+	visible := m.depRows()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	} else if v.cursor >= v.offset+visible {
+		v.offset = v.cursor - visible + 1
+	}
+}
+
+// renderDeps draws the dependency tree in place of both panes
+func (m *Model) renderDeps() string {
+	v := m.deps
+	width := m.width - 4
+	height := m.height - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	unitStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
+	gutter := lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▌")
+
+	relations := systemd.ForwardDependencies
+	direction := "forward"
+	if v.reverse {
+		relations = systemd.ReverseDependencies
+		direction = "reverse"
+	}
+	header := titleStyle.Render(fmt.Sprintf("DEPENDENCIES · %s (%s: %s)",
+		v.root.dep.Unit, direction, strings.Join(relations, ", "))) +
+		mutedStyle.Render("  →/space expand • ← collapse • R reverse • enter go to unit • l logs • G export graph • esc close")
+
+	selected := v.selected()
+	line := func(node *depNode, branch string) string {
+		mark := " "
+		if node == selected {
+			mark = gutter
+		}
+
+		expander := "▸ "
+		switch {
+		case node.loading:
+			expander = "… "
+		case node.cyclic():
+			expander = "↺ "
+		case node.loaded && len(node.children) == 0:
+			expander = "  "
+		case node.expanded:
+			expander = "▾ "
+		}
+
+		state := node.dep.SubState
+		if state == "" {
+			state = node.dep.ActiveState
+		}
+		color, symbol := m.theme.stateStyle(node.dep.ActiveState)
+
+		text := branch + expander + lipgloss.NewStyle().Foreground(color).Render(symbol) + " " +
+			unitStyle.Render(node.dep.Unit) + mutedStyle.Render(" "+orDash(state))
+		if node.dep.Relation != "" {
+			text += mutedStyle.Render(" · " + node.dep.Relation)
+		}
+		return mark + text
+	}
+
+	var sb strings.Builder
+	renderTree(&sb, v.root, "", true, depChildren, line)
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+
+	end := min(len(lines), v.offset+m.depRows())
+	body := strings.Join(lines[min(v.offset, end):end], "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Width(m.width - 2).
+		Height(height).
+		MaxHeight(height + 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body))
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+// loadDeps attaches dependencies to a node as if they were just loaded
+func loadDeps(m *Model, node *depNode, units ...string) {
+	deps := make([]types.Dependency, len(units))
+	for i, unit := range units {
+		deps[i] = types.Dependency{Unit: unit}
+	}
+	node.expanded, node.loading = true, true
+	m.handleDepsLoaded(depsLoadedMsg{view: m.deps, node: node, deps: deps})
+}
+
+// visibleUnits lists the units of the shown nodes in tree order
+func visibleUnits(v *depView) []string {
+	var units []string
+	for _, node := range v.visible() {
+		units = append(units, node.dep.Unit)
+	}
+	return units
+}
+
+func TestDepTree(t *testing.T) {
+	m := &Model{}
+	m.deps = &depView{root: &depNode{dep: types.Dependency{Unit: "app.service"}}}
+	root := m.deps.root
+
+	loadDeps(m, root, "db.service", "network.target")
+	db := root.children[0]
+	loadDeps(m, db, "network.target", "app.service")
+
+	for _, child := range root.children {
+		if child.parent != root {
+			t.Errorf("%s: parent is not the root", child.dep.Unit)
+		}
+	}
+
+	want := []string{"app.service", "db.service", "network.target", "app.service", "network.target"}
+	if got := visibleUnits(m.deps); !reflect.DeepEqual(got, want) {
+		t.Errorf("visible %v, want %v", got, want)
+	}
+
+	// app.service under db.service closes a cycle and isn't expanded again
+	if cycle := db.children[1]; !cycle.cyclic() {
+		t.Error("app.service under db.service isn't cyclic")
+	}
+	if db.children[0].cyclic() || db.cyclic() {
+		t.Error("acyclic node reported as cyclic")
+	}
+	if cmd := m.expandDep(db.children[1]); cmd != nil {
+		t.Error("expanding a cyclic node loads it")
+	}
+
+	// Collapsed nodes hide their children
+	db.expanded = false
+	want = []string{"app.service", "db.service", "network.target"}
+	if got := visibleUnits(m.deps); !reflect.DeepEqual(got, want) {
+		t.Errorf("visible after collapsing %v, want %v", got, want)
+	}
+
+	var sb strings.Builder
+	renderTree(&sb, root, "", true, depChildren, func(node *depNode, branch string) string {
+		return branch + node.dep.Unit
+	})
+	wantTree := "┌─app.service\n  ├─db.service\n  └─network.target\n"
+	if got := sb.String(); got != wantTree {
+		t.Errorf("tree rendered as\n%s\nwant\n%s", got, wantTree)
+	}
+}
+
+func TestDepTreeLoadFailures(t *testing.T) {
+	m := &Model{}
+	m.deps = &depView{root: &depNode{dep: types.Dependency{Unit: "app.service"}}}
+	root := m.deps.root
+
+	// Results for a tree that was closed or replaced are dropped
+	stale := &depView{root: &depNode{}}
+	m.handleDepsLoaded(depsLoadedMsg{view: stale, node: root, deps: []types.Dependency{{Unit: "db.service"}}})
+	if root.loaded || len(root.children) > 0 {
+		t.Error("stale dependencies attached")
+	}
+
+	// A failed load collapses the node so it can be retried
+	root.expanded, root.loading = true, true
+	m.handleDepsLoaded(depsLoadedMsg{view: m.deps, node: root, err: errors.New("no such unit")})
+	if root.expanded || root.loading || root.loaded {
+		t.Errorf("after a failed load: expanded %v, loading %v, loaded %v", root.expanded, root.loading, root.loaded)
+	}
+	if m.errMsg == "" {
+		t.Error("failure not reported")
+	}
+}
@@ -0,0 +1,272 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// envView shows a service's commands and configured environment next to
+// its main process's actual environment
+type envView struct {
+	unit      string
+	env       *types.ServiceEnvironment // nil while loading
+	diffsOnly bool                      // Hide variables that match
+	offset    int                       // First rendered line
+}
+
+// envLoadedMsg carries a service's commands and environment
+type envLoadedMsg struct {
+	unit string
+	env  *types.ServiceEnvironment
+	err  error
+}
+
+// openEnvironment shows the environment viewer for the current service
+func (m *Model) openEnvironment() tea.Cmd {
+	m.environment = &envView{unit: m.currentService}
+	return m.loadEnvironment()
+}
+
+// loadEnvironment reads the service's commands and environment
+func (m *Model) loadEnvironment() tea.Cmd {
+	unit := m.environment.unit
+	return func() tea.Msg {
+		env, err := m.manager.GetServiceEnvironment(unit)
+		return envLoadedMsg{unit: unit, env: env, err: err}
+	}
+}
+
+// handleEnvLoaded shows a loaded environment
+func (m *Model) handleEnvLoaded(msg envLoadedMsg) {
+	if m.environment == nil || m.environment.unit != msg.unit {
+		return
+	}
+	if msg.err != nil {
+		m.environment = nil
+		m.errMsg = fmt.Sprintf("Failed to read environment: %v", msg.err)
+		return
+	}
+	m.environment.env = msg.env
+	m.scrollEnvironment()
+}
+
+// updateEnvironment handles keys while the environment viewer is open
+func (m *Model) updateEnvironment(msg tea.KeyMsg) tea.Cmd {
+	v := m.environment
+
+	switch msg.String() {
+	case "esc", "E", "q":
+		m.environment = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.offset = max(0, v.offset-1)
+	case "down", "j":
+		v.offset++
+	case "pgup":
+		v.offset = max(0, v.offset-m.logViewport.Height/2)
+	case "pgdown":
+		v.offset += m.logViewport.Height / 2
+	case "d":
+		v.diffsOnly = !v.diffsOnly
+		v.offset = 0
+	case "R":
+		return m.loadEnvironment()
+	}
+	m.scrollEnvironment()
+	return nil
+}
+
+// environmentRows is how many lines fit below the viewer's header
+func (m *Model) environmentRows() int {
+	// The header takes one line
+	return max(1, m.logViewport.Height-2-1)
+}
+
+// scrollEnvironment keeps the viewer scrolled within its lines
+func (m *Model) scrollEnvironment() {
+	v := m.environment
+	if v == nil || v.env == nil {
+		return
+	}
+	v.offset = max(0, min(v.offset, len(m.environmentLines(v.env))-m.environmentRows()))
+}
+
+// renderEnvironment draws the environment viewer in place of the logs:
+// identity, command lines with their last exit, environment files, then
+// configured and runtime variables side by side
+func (m *Model) renderEnvironment() string {
+	v := m.environment
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	header := titleStyle.Render("ENVIRONMENT · "+v.unit) +
+		labelStyle.Render("  ↑↓ scroll • d differences only • R refresh • esc close")
+	if v.env == nil {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Reading unit properties..."))
+	}
+
+	lines := m.environmentLines(v.env)
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+	end := min(len(lines), v.offset+m.environmentRows())
+
+	return m.statsFrame(header + "\n" + strings.Join(lines[min(v.offset, end):end], "\n"))
+}
+
+// environmentLines lays out the identity, command lines with their last
+// exit, environment files and variables
+func (m *Model) environmentLines(env *types.ServiceEnvironment) []string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var lines []string
+	section := func(title string) {
+		lines = append(lines, "", titleStyle.Render(title))
+	}
+
+	identity := []string{
+		labelStyle.Render("User ") + orDefault(env.User, "root"),
+		labelStyle.Render("Group ") + orDefault(env.Group, "(user's)"),
+		labelStyle.Render("WorkingDirectory ") + orDefault(env.WorkingDirectory, "/"),
+	}
+	if env.MainPID > 0 {
+		identity = append(identity, labelStyle.Render("MainPID ")+fmt.Sprint(env.MainPID))
+	}
+	lines = append(lines, strings.Join(identity, "   "))
+
+	section("COMMANDS")
+	if len(env.Commands) == 0 {
+		lines = append(lines, labelStyle.Render("No command lines"))
+	}
+	for _, cmd := range env.Commands {
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("%-14s", cmd.Directive))+m.commandStatus(cmd))
+		lines = append(lines, "  "+commandLine(cmd))
+	}
+
+	if len(env.Files) > 0 {
+		section("ENVIRONMENT FILES")
+	}
+	for _, f := range env.Files {
+		switch {
+		case f.Err != "" && f.Optional:
+			lines = append(lines, f.Path+labelStyle.Render(" - skipped (optional): "+f.Err))
+		case f.Err != "":
+			lines = append(lines, f.Path+lipgloss.NewStyle().Foreground(m.theme.Error).Render(" - "+f.Err))
+		default:
+			lines = append(lines, f.Path+labelStyle.Render(fmt.Sprintf(" - %d variables", len(f.Vars))))
+		}
+	}
+
+	section("VARIABLES")
+	return append(lines, m.envLines(env, m.logViewport.Width-2)...)
+}
+
+// envLines lays out configured and runtime variables in two columns,
+// colored by whether they match
+func (m *Model) envLines(env *types.ServiceEnvironment, width int) []string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	v := m.environment
+
+	keyWidth := 0
+	for _, ev := range env.Vars {
+		keyWidth = max(keyWidth, len(ev.Key))
+	}
+	keyWidth = min(keyWidth, 24)
+	// Marker, key, two columns and the separator
+	colWidth := max(10, (width-keyWidth-7)/2)
+
+	lines := []string{
+		labelStyle.Render("= same  ≠ differs  − not in the process  + set by systemd or the caller"),
+		labelStyle.Render(fmt.Sprintf("  %-*s  %-*s │ %s", keyWidth, "", colWidth, "configured", "runtime")),
+	}
+	if env.RuntimeErr != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Warning).
+			Render("Runtime environment unavailable: "+env.RuntimeErr))
+	}
+
+	shown := 0
+	for _, ev := range env.Vars {
+		marker, color := "=", m.theme.Text
+		switch {
+		case env.RuntimeErr != "":
+			marker, color = " ", m.theme.Text
+		case ev.InConfig && !ev.InRuntime:
+			marker, color = "−", m.theme.Error
+		case !ev.InConfig:
+			marker, color = "+", m.theme.Muted
+		case ev.Configured != ev.Runtime:
+			marker, color = "≠", m.theme.Warning
+		}
+		if v.diffsOnly && (marker == "=" || marker == "+") {
+			continue
+		}
+		shown++
+
+		configured := ev.Configured
+		if ev.InConfig && ev.Source != "Environment=" {
+			configured += " (" + ev.Source + ")"
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%s %-*s  %-*s │ %s",
+			marker, keyWidth, truncate(ev.Key, keyWidth),
+			colWidth, truncate(configured, colWidth), truncate(ev.Runtime, colWidth))))
+	}
+	switch {
+	case shown > 0:
+	case v.diffsOnly:
+		lines = append(lines, labelStyle.Render("No differences"))
+	default:
+		lines = append(lines, labelStyle.Render("No variables"))
+	}
+	return lines
+}
+
+// commandStatus describes how a command line last ran
+func (m *Model) commandStatus(cmd types.ExecCommand) string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	switch {
+	case cmd.PID == 0:
+		return labelStyle.Render("not run")
+	case cmd.ExitedAt.IsZero():
+		return lipgloss.NewStyle().Foreground(m.theme.Success).
+			Render(fmt.Sprintf("running, PID %d, since %s", cmd.PID, cmd.StartedAt.Format("2006-01-02 15:04:05")))
+	}
+
+	color := m.theme.Success
+	if cmd.ExitCode != systemd.CLDExited || cmd.ExitStatus != 0 {
+		color = m.theme.Error
+		if cmd.IgnoreFailure {
+			color = m.theme.Warning
+		}
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(systemd.DescribeExit(cmd.ExitCode, cmd.ExitStatus)) +
+		labelStyle.Render(fmt.Sprintf(", PID %d, at %s", cmd.PID, cmd.ExitedAt.Format("2006-01-02 15:04:05")))
+}
+
+// commandLine formats a command as written in the unit file
+func commandLine(cmd types.ExecCommand) string {
+	line := strings.Join(cmd.Argv, " ")
+	if line == "" {
+		line = cmd.Path
+	}
+	if cmd.IgnoreFailure {
+		line = "-" + line
+	}
+	return line
+}
+
+// orDefault returns value, or what applies when it's empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
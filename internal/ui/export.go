@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"sdtop/internal/export"
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotLogCount is how many recent log entries a snapshot includes
+const snapshotLogCount = 1000
+
+// graphOptions are used for graphs exported from the UI: two levels of
+// every edge type, without low-level units
+var graphOptions = systemd.GraphOptions{Depth: 2, ExcludeSystem: true}
+
+// exportFileName suggests a file name for exporting the displayed logs
+func (m *Model) exportFileName() string {
+	unit := m.currentService
+	if m.merged {
+		unit = "merged"
+	}
+	return export.DefaultFileName("sdtop", unit, ".log")
+}
+
+// displayedLogs returns the entries shown in the logs pane: everything
+// loaded for the current filters and time range, narrowed to the search
+// matches when a search is active
+func (m *Model) displayedLogs() []types.LogEntry {
+	if !m.search.active() {
+		return m.logs.Slice()
+	}
+
+	logs := make([]types.LogEntry, 0, len(m.search.matches))
+	for _, idx := range m.search.matches {
+		logs = append(logs, m.logs.At(idx))
+	}
+	return logs
+}
+
+// exportLogs writes the displayed logs to a file, picking the format
+// from its extension
+func (m *Model) exportLogs(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+
+	logs := m.displayedLogs()
+	format := export.FormatFromPath(path)
+
+	return func() tea.Msg {
+		if err := export.WriteLogsFile(path, logs, format); err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to export logs: %v", err))
+		}
+		return statusMsgType(fmt.Sprintf("Exported %d entries to %s (%s)", len(logs), path, format))
+	}
+}
+
+// saveSnapshot bundles the current service's status, properties, unit
+// file, process tree and recent logs into a .tar.gz archive
+func (m *Model) saveSnapshot(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+
+	unit := m.currentService
+	return func() tea.Msg {
+		props, err := m.manager.GetUnitProperties(unit)
+		if err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to read %s properties: %v", unit, err))
+		}
+
+		// A stopped service has no processes, which is worth recording too
+		processes, _ := m.processManager.GetServiceProcesses(unit, nil)
+
+		q := systemd.LogQuery{Units: []string{unit}, MaxPriority: types.PriorityDebug}
+		logs, err := m.logReader.GetRecentLogs(q, snapshotLogCount)
+		if err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to read %s logs: %v", unit, err))
+		}
+
+		snapshot := &export.Snapshot{
+			Unit:       unit,
+			Taken:      time.Now(),
+			Properties: props,
+			Processes:  processes,
+			Logs:       logs,
+		}
+		if err := export.WriteSnapshot(path, snapshot); err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to write snapshot: %v", err))
+		}
+		return statusMsgType(fmt.Sprintf("Saved snapshot of %s to %s", unit, path))
+	}
+}
+
+// promptGraph asks where to export the current unit's dependency graph
+func (m *Model) promptGraph() tea.Cmd {
+	return m.prompt.open(promptGraph, "export dependency graph to: ",
+		export.DefaultFileName("sdtop-graph", m.currentService, ".dot"),
+		".dot Graphviz • .mmd Mermaid")
+}
+
+// exportGraph writes the current unit's dependency and ordering graph,
+// picking the format from the file extension
+func (m *Model) exportGraph(path string) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+
+	unit := m.currentService
+	format := export.GraphFormatFromPath(path)
+	return func() tea.Msg {
+		graph, err := m.manager.GetUnitGraph(unit, graphOptions)
+		if err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to build the graph of %s: %v", unit, err))
+		}
+		if err := export.WriteGraphFile(path, graph, format); err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to export graph: %v", err))
+		}
+		return statusMsgType(fmt.Sprintf("Exported %s graph of %s (%d units, %d edges) to %s",
+			format, unit, len(graph.States), len(graph.Edges), path))
+	}
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stateWatchMsg is sent once systemd reports service state changes
+type stateWatchMsg struct {
+	changes <-chan types.StateChange
+}
+
+// stateChangedMsg carries one service state change
+type stateChangedMsg struct {
+	change  types.StateChange
+	changes <-chan types.StateChange
+}
+
+// watchStates subscribes to service state changes from systemd
+func (m *Model) watchStates() tea.Msg {
+	changes, err := m.manager.WatchServiceStates()
+	if err != nil {
+		return systemd.ErrorMsg(fmt.Sprintf("Failed to watch service states: %v", err))
+	}
+	return stateWatchMsg{changes: changes}
+}
+
+// waitForStateChange waits for the next service state change
+func waitForStateChange(changes <-chan types.StateChange) tea.Cmd {
+	return func() tea.Msg {
+		change, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return stateChangedMsg{change: change, changes: changes}
+	}
+}
+
+// handleStateChange records a state change for flap detection and updates
+// the service's state in the list
+func (m *Model) handleStateChange(msg stateChangedMsg) tea.Cmd {
+	change := msg.change
+	wasFlapping := m.flaps.Flapping(change.Unit)
+	m.flaps.Record(change)
+
+	update := func(services []types.Service) {
+		for i := range services {
+			if services[i].Name == change.Unit {
+				services[i].ActiveState = change.ActiveState
+				if change.SubState != "" {
+					services[i].SubState = change.SubState
+				}
+			}
+		}
+	}
+	update(m.allServices)
+	update(m.services)
+
+	cmds := []tea.Cmd{waitForStateChange(msg.changes)}
+	for _, svc := range m.allServices {
+		if svc.Name == change.Unit {
+			cmds = append(cmds, m.refreshServiceItem(svc))
+			break
+		}
+	}
+	if m.filterMode == "flapping" && wasFlapping != m.flaps.Flapping(change.Unit) {
+		cmds = append(cmds, m.applyFilter())
+	}
+	return tea.Batch(cmds...)
+}
+
+// flappingServices returns the flapping services, most restarts first
+func (m *Model) flappingServices() []types.Service {
+	var flapping []types.Service
+	for _, svc := range m.allServices {
+		if m.flaps.Flapping(svc.Name) {
+			flapping = append(flapping, svc)
+		}
+	}
+
+	sort.SliceStable(flapping, func(i, j int) bool {
+		si, _ := m.flaps.Counts(flapping[i].Name)
+		sj, _ := m.flaps.Counts(flapping[j].Name)
+		return si > sj
+	})
+	return flapping
+}
+
+// renderFlapTimeline draws a one-line restart timeline of a flapping
+// service, newest changes last, trimmed to fit width
+func (m *Model) renderFlapTimeline(unit string, width int) string {
+	starts, failures := m.flaps.Counts(unit)
+	header := lipgloss.NewStyle().Foreground(m.theme.Error).Bold(true).
+		Render(fmt.Sprintf("⟳ %d starts, %d failures in %s", starts, failures, m.flaps.Window)) +
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" │ ")
+
+	var events []string
+	for _, change := range m.flaps.Timeline(unit) {
+		color := m.theme.Muted
+		switch change.ActiveState {
+		case "active":
+			color = m.theme.Success
+		case "failed":
+			color = m.theme.Error
+		case "activating", "deactivating":
+			color = m.theme.Warning
+		}
+		events = append(events, lipgloss.NewStyle().Foreground(color).
+			Render(change.Time.Format("15:04:05")+" "+change.ActiveState))
+	}
+
+	// Keep the most recent events that fit
+	sep := lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" → ")
+	room := width - lipgloss.Width(header)
+	line := ""
+	for i := len(events) - 1; i >= 0; i-- {
+		candidate := events[i]
+		if line != "" {
+			candidate += sep + line
+		}
+		if lipgloss.Width(candidate) > room {
+			line = "… " + line
+			break
+		}
+		line = candidate
+	}
+
+	return header + strings.TrimSpace(line)
+}
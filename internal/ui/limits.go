@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sdtop/internal/systemd"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// limitsRefresh is how often the usage above the limits form is updated
+const limitsRefresh = 2 * time.Second
+
+// limitsView is the form for changing a running unit's resource limits
+type limitsView struct {
+	unit      string
+	props     map[string]interface{} // nil while loading
+	inputs    []textinput.Model      // One per systemd.ResourceLimits entry
+	initial   []string               // Input values as loaded, to send only changes
+	errs      []string               // Validation error per input
+	focus     int
+	runtime   bool // Apply until reboot only, instead of persistently
+	refreshed time.Time
+}
+
+// limitsLoadedMsg carries a unit's properties for the limits form. reset
+// refills the inputs with the current limits.
+type limitsLoadedMsg struct {
+	unit   string
+	props  map[string]interface{}
+	reset  bool
+	status string
+	err    error
+}
+
+// openLimits shows the resource limits form for the current service
+func (m *Model) openLimits() tea.Cmd {
+	m.limits = &limitsView{unit: m.currentService, runtime: true}
+	return m.loadLimits(true, "")
+}
+
+// loadLimits reads the unit's limits and resource usage
+func (m *Model) loadLimits(reset bool, status string) tea.Cmd {
+	unit := m.limits.unit
+	m.limits.refreshed = time.Now()
+
+	return func() tea.Msg {
+		props, err := m.manager.GetUnitProperties(unit)
+		return limitsLoadedMsg{unit: unit, props: props, reset: reset, status: status, err: err}
+	}
+}
+
+// handleLimitsLoaded shows fresh usage, and current limits in the inputs
+// when the form is opened or after applying
+func (m *Model) handleLimitsLoaded(msg limitsLoadedMsg) tea.Cmd {
+	v := m.limits
+	if v == nil || v.unit != msg.unit {
+		return nil
+	}
+	if msg.err != nil {
+		m.limits = nil
+		m.errMsg = fmt.Sprintf("Failed to read %s limits: %v", msg.unit, msg.err)
+		return nil
+	}
+	v.props = msg.props
+
+	if msg.reset {
+		current := systemd.CurrentLimits(msg.props)
+		v.inputs = make([]textinput.Model, len(systemd.ResourceLimits))
+		v.initial = make([]string, len(systemd.ResourceLimits))
+		v.errs = make([]string, len(systemd.ResourceLimits))
+		for i, l := range systemd.ResourceLimits {
+			input := textinput.New()
+			input.Prompt = ""
+			input.CharLimit = 16
+			input.Width = 12
+			input.SetValue(systemd.FormatLimit(l.Name, current[l.Name]))
+			v.inputs[i] = input
+			v.initial[i] = input.Value()
+		}
+		v.focus = min(v.focus, len(v.inputs)-1)
+		v.inputs[v.focus].Focus()
+	}
+
+	if msg.status != "" {
+		return func() tea.Msg { return statusMsgType(msg.status) }
+	}
+	return nil
+}
+
+// refreshLimits updates the usage shown in the form periodically
+func (m *Model) refreshLimits() tea.Cmd {
+	if m.limits == nil || m.limits.props == nil || time.Since(m.limits.refreshed) < limitsRefresh {
+		return nil
+	}
+	return m.loadLimits(false, "")
+}
+
+// updateLimits handles keys in the limits form
+func (m *Model) updateLimits(msg tea.KeyMsg) tea.Cmd {
+	v := m.limits
+	if v.props == nil {
+		if msg.String() == "esc" {
+			m.limits = nil
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.limits = nil
+		return nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "shift+tab":
+		return v.focusInput(v.focus - 1)
+	case "down", "tab":
+		return v.focusInput(v.focus + 1)
+	case "ctrl+r":
+		v.runtime = !v.runtime
+		return nil
+	case "enter":
+		return m.applyLimits()
+	}
+
+	var cmd tea.Cmd
+	v.inputs[v.focus], cmd = v.inputs[v.focus].Update(msg)
+	v.errs[v.focus] = ""
+	return cmd
+}
+
+// focusInput moves the cursor to another input, wrapping around
+func (v *limitsView) focusInput(i int) tea.Cmd {
+	v.inputs[v.focus].Blur()
+	v.focus = (i + len(v.inputs)) % len(v.inputs)
+	return v.inputs[v.focus].Focus()
+}
+
+// applyLimits validates the changed inputs and sets them on the unit
+func (m *Model) applyLimits() tea.Cmd {
+	v := m.limits
+
+	changed := map[string]uint64{}
+	var names []string
+	valid := true
+	for i, l := range systemd.ResourceLimits {
+		value := strings.TrimSpace(v.inputs[i].Value())
+		if value == v.initial[i] {
+			continue
+		}
+		n, err := systemd.ParseLimit(l.Name, value)
+		if err != nil {
+			v.errs[i] = err.Error()
+			valid = false
+			continue
+		}
+		changed[l.Name] = n
+		names = append(names, l.Name+"="+value)
+	}
+	if !valid {
+		return nil
+	}
+	if len(changed) == 0 {
+		return func() tea.Msg { return statusMsgType("No limits changed") }
+	}
+
+	unit, runtime := v.unit, v.runtime
+	mode := "persistently"
+	if runtime {
+		mode = "until reboot"
+	}
+	return func() tea.Msg {
+		if err := m.manager.SetResourceLimits(unit, runtime, changed); err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to set limits on %s: %v", unit, err))
+		}
+		// Read back what systemd applied, so the usage shows the effect
+		props, err := m.manager.GetUnitProperties(unit)
+		status := fmt.Sprintf("Set %s on %s %s", strings.Join(names, " "), unit, mode)
+		return limitsLoadedMsg{unit: unit, props: props, reset: true, status: status, err: err}
+	}
+}
+
+// renderLimits draws the limits form in place of the logs: current usage
+// against the limits, then an input per limit
+func (m *Model) renderLimits() string {
+	v := m.limits
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	errStyle := lipgloss.NewStyle().Foreground(m.theme.Error)
+
+	header := titleStyle.Render("RESOURCE LIMITS · "+v.unit) +
+		labelStyle.Render("  ↑↓ field • enter apply • ctrl+r runtime/persistent • esc close")
+	if v.props == nil {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Reading unit properties..."))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n\n")
+
+	// Usage against the limits that apply to it
+	current := systemd.CurrentLimits(v.props)
+	barWidth := max(10, min(40, width-40))
+	if mem, ok := usageProp(v.props, "MemoryCurrent"); ok {
+		sb.WriteString(m.usageLine("Memory", mem, current["MemoryMax"], systemd.FormatBytes, barWidth))
+	}
+	if tasks, ok := usageProp(v.props, "TasksCurrent"); ok {
+		sb.WriteString(m.usageLine("Tasks", tasks, current["TasksMax"],
+			func(n uint64) string { return fmt.Sprint(n) }, barWidth))
+	}
+	if cpu, ok := usageProp(v.props, "CPUUsageNSec"); ok {
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%-8s", "CPU")) +
+			fmt.Sprintf("%s used", time.Duration(cpu).Round(time.Millisecond)) +
+			labelStyle.Render("  quota "+systemd.FormatLimit("CPUQuota", current["CPUQuota"])) + "\n")
+	}
+	if high := current["MemoryHigh"]; high != unsetUsage {
+		mem, _ := usageProp(v.props, "MemoryCurrent")
+		if mem >= high {
+			sb.WriteString(lipgloss.NewStyle().Foreground(m.theme.Warning).
+				Render("⚠ memory is above MemoryHigh and being throttled") + "\n")
+		}
+	}
+
+	mode := lipgloss.NewStyle().Foreground(m.theme.Success).Render("runtime only (until reboot)")
+	if !v.runtime {
+		mode = lipgloss.NewStyle().Foreground(m.theme.Warning).Render("persistent (writes a drop-in)")
+	}
+	sb.WriteString("\n" + labelStyle.Render("Apply: ") + mode + "\n\n")
+
+	for i, l := range systemd.ResourceLimits {
+		marker := "  "
+		if i == v.focus {
+			marker = titleStyle.Render("▶ ")
+		}
+		line := marker + fmt.Sprintf("%-11s", l.Name) + "[" + v.inputs[i].View() + "]"
+		if v.inputs[i].Value() != v.initial[i] {
+			was := v.initial[i]
+			if was == "" {
+				was = "unset"
+			}
+			line += labelStyle.Render(" was " + was)
+		}
+		sb.WriteString(line + "\n")
+
+		switch {
+		case v.errs[i] != "":
+			sb.WriteString("    " + errStyle.Render("✗ "+v.errs[i]) + "\n")
+		case i == v.focus:
+			sb.WriteString("    " + labelStyle.Render(truncate(l.Help, max(10, width-4))) + "\n")
+		}
+	}
+
+	return m.statsFrame(sb.String())
+}
+
+// unsetUsage is how systemd reports unset limits and unknown usage
+const unsetUsage = ^uint64(0)
+
+// usageProp returns a usage counter, which systemd reports as the maximum
+// value when accounting is off
+func usageProp(props map[string]interface{}, key string) (uint64, bool) {
+	v, ok := props[key].(uint64)
+	return v, ok && v != unsetUsage
+}
+
+// usageLine shows a usage value with a bar filled up to its limit
+func (m *Model) usageLine(label string, used, limit uint64, format func(uint64) string, barWidth int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	line := labelStyle.Render(fmt.Sprintf("%-8s", label)) + format(used)
+
+	if limit == unsetUsage || limit == 0 {
+		return line + labelStyle.Render(" (no limit)") + "\n"
+	}
+
+	ratio := min(1, float64(used)/float64(limit))
+	filled := int(ratio * float64(barWidth))
+	color := m.theme.Success
+	switch {
+	case ratio >= 0.9:
+		color = m.theme.Error
+	case ratio >= 0.7:
+		color = m.theme.Warning
+	}
+	bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		labelStyle.Render(strings.Repeat("░", barWidth-filled))
+
+	return line + labelStyle.Render(" of "+format(limit)+" ") + bar +
+		labelStyle.Render(fmt.Sprintf(" %.0f%%", ratio*100)) + "\n"
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lintView shows unit file findings for one unit
+type lintView struct {
+	unit     string
+	files    []string
+	findings []types.LintFinding
+	loading  bool
+	offset   int // First rendered line
+}
+
+// lintLoadedMsg carries the findings for a unit's files
+type lintLoadedMsg struct {
+	unit     string
+	files    []string
+	findings []types.LintFinding
+	err      error
+}
+
+// openLint lints the current service's unit file and drop-ins and shows
+// the findings in place of the logs
+func (m *Model) openLint() tea.Cmd {
+	unit := m.currentService
+	m.lint = &lintView{unit: unit, loading: true}
+
+	return func() tea.Msg {
+		files, err := m.manager.UnitFilePaths(unit)
+		if err != nil {
+			return lintLoadedMsg{unit: unit, err: err}
+		}
+		findings, err := systemd.LintUnitFiles(files)
+		return lintLoadedMsg{unit: unit, files: files, findings: findings, err: err}
+	}
+}
+
+// handleLintLoaded shows loaded findings
+func (m *Model) handleLintLoaded(msg lintLoadedMsg) {
+	if m.lint == nil || m.lint.unit != msg.unit {
+		return
+	}
+	if msg.err != nil {
+		m.lint = nil
+		m.errMsg = fmt.Sprintf("Failed to lint %s: %v", msg.unit, msg.err)
+		return
+	}
+	m.lint.loading = false
+	m.lint.files = msg.files
+	m.lint.findings = msg.findings
+	m.scrollLint()
+}
+
+// updateLint handles keys while the lint panel is open
+func (m *Model) updateLint(msg tea.KeyMsg) tea.Cmd {
+	v := m.lint
+
+	switch msg.String() {
+	case "esc", "L", "q":
+		m.lint = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.offset = max(0, v.offset-1)
+	case "down", "j":
+		v.offset++
+	case "R":
+		return m.openLint()
+	}
+	m.scrollLint()
+	return nil
+}
+
+// lintRows is how many lines of findings fit below the summary
+func (m *Model) lintRows() int {
+	// The header, summary and a blank line take three lines
+	return max(1, m.logViewport.Height-2-3)
+}
+
+// scrollLint keeps the panel scrolled within its findings
+func (m *Model) scrollLint() {
+	v := m.lint
+	if v == nil || v.loading {
+		return
+	}
+	v.offset = max(0, min(v.offset, len(m.lintLines())-m.lintRows()))
+}
+
+// lintLines lists each file followed by its findings
+func (m *Model) lintLines() []string {
+	v := m.lint
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var lines []string
+	for _, file := range v.files {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render(file))
+		found := false
+		for _, f := range v.findings {
+			if f.File != file {
+				continue
+			}
+			found = true
+
+			style := lipgloss.NewStyle().Foreground(m.theme.Text)
+			icon := "• "
+			switch f.Severity {
+			case types.LintError:
+				style, icon = style.Foreground(m.theme.Error), "✗ "
+			case types.LintWarning:
+				style, icon = style.Foreground(m.theme.Warning), "⚠ "
+			}
+			location := filepath.Base(file)
+			if f.Line > 0 {
+				location += fmt.Sprintf(":%d", f.Line)
+			}
+			lines = append(lines, "  "+style.Render(icon)+labelStyle.Render(location+" ")+style.Render(f.Message))
+		}
+		if !found {
+			lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Success).Render("  ✓ no problems"))
+		}
+	}
+	return lines
+}
+
+// renderLint draws the lint findings in place of the logs, grouped by
+// file
+func (m *Model) renderLint() string {
+	v := m.lint
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	header := titleStyle.Render("LINT · "+v.unit) + labelStyle.Render("  ↑↓ scroll • [R]erun • esc close")
+	if v.loading {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Checking unit files..."))
+	}
+
+	counts := map[types.LintSeverity]int{}
+	for _, f := range v.findings {
+		counts[f.Severity]++
+	}
+	summary := labelStyle.Render(fmt.Sprintf("%d file(s): ", len(v.files))) +
+		lipgloss.NewStyle().Foreground(m.theme.Error).Render(fmt.Sprintf("%d errors", counts[types.LintError])) +
+		labelStyle.Render(", ") +
+		lipgloss.NewStyle().Foreground(m.theme.Warning).Render(fmt.Sprintf("%d warnings", counts[types.LintWarning])) +
+		labelStyle.Render(fmt.Sprintf(", %d notes", counts[types.LintInfo]))
+
+	lines := m.lintLines()
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+	end := min(len(lines), v.offset+m.lintRows())
+
+	return m.statsFrame(header + "\n" + summary + "\n\n" + strings.Join(lines[min(v.offset, end):end], "\n"))
+}
@@ -0,0 +1,85 @@
+package ui
+
+import "sdtop/internal/types"
+
+// defaultLogBufferSize caps the number of log entries kept in memory
+const defaultLogBufferSize = 5000
+
+// logBuffer is a fixed-capacity ring buffer of log entries, ordered oldest
+// first. Appending past capacity evicts the oldest entries; prepending
+// evicts the newest.
+type logBuffer struct {
+	entries []types.LogEntry
+	start   int // Index of the oldest entry in entries
+	size    int
+}
+
+// newLogBuffer creates an empty buffer holding up to capacity entries
+func newLogBuffer(capacity int) *logBuffer {
+	if capacity <= 0 {
+		capacity = defaultLogBufferSize
+	}
+	return &logBuffer{entries: make([]types.LogEntry, capacity)}
+}
+
+// Len returns the number of entries in the buffer
+func (b *logBuffer) Len() int {
+	return b.size
+}
+
+// Cap returns the maximum number of entries the buffer holds
+func (b *logBuffer) Cap() int {
+	return len(b.entries)
+}
+
+// At returns the i-th entry, oldest first
+func (b *logBuffer) At(i int) types.LogEntry {
+	return b.entries[(b.start+i)%len(b.entries)]
+}
+
+// Reset replaces the contents with the newest entries of logs
+func (b *logBuffer) Reset(logs []types.LogEntry) {
+	b.start, b.size = 0, 0
+	b.Append(logs)
+}
+
+// Append adds entries after the newest one and returns how many of the
+// oldest entries were evicted to make room
+func (b *logBuffer) Append(logs []types.LogEntry) int {
+	evicted := 0
+	for _, log := range logs {
+		if b.size == len(b.entries) {
+			b.start = (b.start + 1) % len(b.entries)
+			b.size--
+			evicted++
+		}
+		b.entries[(b.start+b.size)%len(b.entries)] = log
+		b.size++
+	}
+	return evicted
+}
+
+// Prepend adds entries (oldest first) before the oldest one and returns
+// how many of the newest entries were evicted to make room
+func (b *logBuffer) Prepend(logs []types.LogEntry) int {
+	evicted := 0
+	for i := len(logs) - 1; i >= 0; i-- {
+		if b.size == len(b.entries) {
+			b.size--
+			evicted++
+		}
+		b.start = (b.start - 1 + len(b.entries)) % len(b.entries)
+		b.entries[b.start] = logs[i]
+		b.size++
+	}
+	return evicted
+}
+
+// Slice returns a copy of the entries, oldest first
+func (b *logBuffer) Slice() []types.LogEntry {
+	logs := make([]types.LogEntry, b.size)
+	for i := range logs {
+		logs[i] = b.At(i)
+	}
+	return logs
+}
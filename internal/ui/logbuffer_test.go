@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"sdtop/internal/types"
+)
+
+// logEntries makes one entry per cursor
+func logEntries(cursors ...string) []types.LogEntry {
+	logs := make([]types.LogEntry, len(cursors))
+	for i, cursor := range cursors {
+		logs[i] = types.LogEntry{Cursor: cursor}
+	}
+	return logs
+}
+
+// bufferCursors lists the cursors in the buffer, oldest first
+func bufferCursors(b *logBuffer) []string {
+	cursors := []string{}
+	for _, log := range b.Slice() {
+		cursors = append(cursors, log.Cursor)
+	}
+	return cursors
+}
+
+func TestLogBuffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		capacity    int
+		initial     []string
+		append      []string
+		prepend     []string
+		want        []string
+		wantEvicted int
+	}{
+		{"append below capacity", 4, []string{"a", "b"}, []string{"c"}, nil, []string{"a", "b", "c"}, 0},
+		{"append to capacity", 3, []string{"a", "b"}, []string{"c"}, nil, []string{"a", "b", "c"}, 0},
+		{"append past capacity", 3, []string{"a", "b", "c"}, []string{"d", "e"}, nil, []string{"c", "d", "e"}, 2},
+		{"append more than capacity", 2, []string{"a"}, []string{"b", "c", "d", "e"}, nil, []string{"d", "e"}, 3},
+		{"reset keeps the newest", 2, []string{"a", "b", "c"}, nil, nil, []string{"b", "c"}, 0},
+		{"prepend below capacity", 4, []string{"c", "d"}, nil, []string{"a", "b"}, []string{"a", "b", "c", "d"}, 0},
+		{"prepend when full", 3, []string{"c", "d", "e"}, nil, []string{"a", "b"}, []string{"a", "b", "c"}, 2},
+		{"prepend more than capacity", 2, []string{"e"}, nil, []string{"a", "b", "c", "d"}, []string{"a", "b"}, 3},
+		// The ring has wrapped, so the oldest entry isn't at index 0
+		{"prepend after wrap", 3, []string{"a", "b", "c", "d"}, nil, []string{"x"}, []string{"x", "b", "c"}, 1},
+		{"append after wrap", 3, []string{"a", "b", "c", "d", "e"}, []string{"f"}, nil, []string{"d", "e", "f"}, 1},
+	}
+
+	for _, tt := range tests {
+		b := newLogBuffer(tt.capacity)
+		b.Reset(logEntries(tt.initial...))
+
+		evicted := 0
+		if tt.append != nil {
+			evicted = b.Append(logEntries(tt.append...))
+		}
+		if tt.prepend != nil {
+			evicted = b.Prepend(logEntries(tt.prepend...))
+		}
+
+		if got := bufferCursors(b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: cursors %v, want %v", tt.name, got, tt.want)
+		}
+		if evicted != tt.wantEvicted {
+			t.Errorf("%s: evicted %d, want %d", tt.name, evicted, tt.wantEvicted)
+		}
+		if b.Len() != len(tt.want) || b.Cap() != tt.capacity {
+			t.Errorf("%s: len %d cap %d, want %d and %d", tt.name, b.Len(), b.Cap(), len(tt.want), tt.capacity)
+		}
+	}
+}
+
+func TestLogBufferAt(t *testing.T) {
+	b := newLogBuffer(3)
+	b.Reset(logEntries("a", "b", "c"))
+	b.Append(logEntries("d"))
+	b.Prepend(logEntries("z"))
+
+	// Entries come out oldest first however often the ring has wrapped
+	want := []string{"z", "b", "c"}
+	for i, cursor := range want {
+		if got := b.At(i).Cursor; got != cursor {
+			t.Errorf("At(%d) = %s, want %s", i, got, cursor)
+		}
+	}
+
+	if got := newLogBuffer(0).Cap(); got != defaultLogBufferSize {
+		t.Errorf("default capacity %d, want %d", got, defaultLogBufferSize)
+	}
+}
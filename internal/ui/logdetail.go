@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// selectedLog returns the index of the selected log entry, or -1
+func (m *Model) selectedLog() int {
+	if m.selectedCursor == "" {
+		return -1
+	}
+	for i := m.logs.Len() - 1; i >= 0; i-- {
+		if m.logs.At(i).Cursor == m.selectedCursor {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectLog selects a log entry and scrolls it into view
+func (m *Model) selectLog(idx int) {
+	if idx < 0 || idx >= m.logs.Len() {
+		return
+	}
+	m.selectedCursor = m.logs.At(idx).Cursor
+	m.logViewport.SetContent(m.formatLogs())
+
+	line := m.logLine(idx)
+	if line < m.logViewport.YOffset {
+		m.logViewport.SetYOffset(line)
+	} else if bottom := m.logViewport.YOffset + m.logViewport.Height; line >= bottom {
+		m.logViewport.SetYOffset(line - m.logViewport.Height + 1)
+	}
+}
+
+// moveLogSelection moves the selection up or down, paging in entries when
+// moving past either end of the window
+func (m *Model) moveLogSelection(delta int) tea.Cmd {
+	if m.logs.Len() == 0 {
+		return nil
+	}
+
+	idx := m.selectedLog()
+	if idx < 0 {
+		// Start from the last entry on screen
+		m.selectLog(m.lastVisibleLog())
+		return m.syncCatalog()
+	}
+
+	next := idx + delta
+	switch {
+	case next < 0:
+		return m.loadOlderLogs()
+	case next >= m.logs.Len():
+		if !m.logsAtTail {
+			return m.loadNewerLogs(false)
+		}
+		return nil
+	}
+
+	m.selectLog(next)
+	return m.syncCatalog()
+}
+
+// lastVisibleLog returns the index of the last entry starting on screen
+func (m *Model) lastVisibleLog() int {
+	bottom := m.logViewport.YOffset + m.logViewport.Height
+	line := 0
+	for i := 0; i < m.logs.Len(); i++ {
+		line += 1 + strings.Count(m.logs.At(i).Message, "\n")
+		if line >= bottom {
+			return i
+		}
+	}
+	return m.logs.Len() - 1
+}
+
+// openLogDetail shows every field of the selected entry in a popup
+func (m *Model) openLogDetail() {
+	idx := m.selectedLog()
+	if idx < 0 {
+		return
+	}
+
+	entry := m.logs.At(idx)
+	m.detail = &entry
+	// Leave room for the popup border and title
+	m.detailViewport = viewport.New(m.logViewport.Width-4, m.logViewport.Height-3)
+	m.detailViewport.KeyMap = logViewportKeyMap()
+	m.detailViewport.SetContent(m.formatLogDetail(entry))
+}
+
+// updateLogDetail handles keys while the detail popup is open
+func (m *Model) updateLogDetail(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "enter", "q":
+		m.detail = nil
+		return nil
+	case "y":
+		return copyLogEntry(*m.detail)
+	case "ctrl+c":
+		return tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.detailViewport, cmd = m.detailViewport.Update(msg)
+	return cmd
+}
+
+// formatLogDetail lists all journal fields of an entry, sorted so user
+// fields come first, then trusted (_) and address (__) fields
+func (m *Model) formatLogDetail(entry types.LogEntry) string {
+	keyStyle := lipgloss.NewStyle().Foreground(m.theme.Accent)
+	valueStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
+
+	keys := make([]string, 0, len(entry.Fields))
+	width := 0
+	for key := range entry.Fields {
+		keys = append(keys, key)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		lines := strings.Split(entry.Fields[key], "\n")
+		sb.WriteString(keyStyle.Render(fmt.Sprintf("%-*s", width, key)))
+		sb.WriteString(" ")
+		sb.WriteString(valueStyle.Render(lines[0]))
+		sb.WriteString("\n")
+		for _, line := range lines[1:] {
+			sb.WriteString(strings.Repeat(" ", width+1))
+			sb.WriteString(valueStyle.Render(line))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// renderLogDetail draws the detail popup in place of the logs
+func (m *Model) renderLogDetail() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Accent).
+		Render(fmt.Sprintf("ENTRY %s", m.detail.Timestamp.Format("2006-01-02 15:04:05.000000"))) +
+		lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Render("  [y]ank raw • esc close")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Width(m.logViewport.Width - 2).
+		Height(m.logViewport.Height - 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, m.detailViewport.View()))
+}
+
+// copyLogEntry copies an entry's fields as JSON (like journalctl -o json)
+// to the system clipboard, and via OSC 52 for remote terminals
+func copyLogEntry(entry types.LogEntry) tea.Cmd {
+	return func() tea.Msg {
+		data, err := json.Marshal(entry.Fields)
+		if err != nil {
+			return systemd.ErrorMsg(fmt.Sprintf("Failed to encode entry: %v", err))
+		}
+
+		termenv.Copy(string(data))
+		if err := clipboard.WriteAll(string(data)); err != nil {
+			return statusMsgType("Copied entry via terminal clipboard (OSC 52)")
+		}
+		return statusMsgType("Copied entry to clipboard")
+	}
+}
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"strings"
+
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logPageSize is how many entries are loaded at a time
+const logPageSize = 100
+
+// logsLoadedMsg is sent when the logs window is (re)loaded from the end
+// of the query range
+type logsLoadedMsg struct {
+	gen  int
+	logs []types.LogEntry
+	err  error
+}
+
+// logsAppendedMsg is sent when entries newer than the window are loaded
+type logsAppendedMsg struct {
+	gen    int
+	logs   []types.LogEntry
+	atTail bool // No newer entries remain in range
+	follow bool // Tail polling rather than scrolling down
+	err    error
+}
+
+// logsPrependedMsg is sent when entries older than the window are loaded
+type logsPrependedMsg struct {
+	gen    int
+	logs   []types.LogEntry
+	atHead bool // No older entries remain in range
+	err    error
+}
+
+// refreshLogs reloads the logs window from the end of the query range.
+// Responses to earlier requests are discarded.
+func (m *Model) refreshLogs() tea.Cmd {
+	m.logGen++
+	m.logsLoading = true
+
+	gen, query := m.logGen, m.logQuery()
+	return func() tea.Msg {
+		logs, err := m.logReader.GetRecentLogs(query, logPageSize)
+		return logsLoadedMsg{gen: gen, logs: logs, err: err}
+	}
+}
+
+// followLogs polls for entries written after the newest one shown
+func (m *Model) followLogs() tea.Cmd {
+	if m.logs.Len() == 0 {
+		return m.refreshLogs()
+	}
+	return m.loadNewerLogs(true)
+}
+
+// loadNewerLogs loads the page after the newest entry in the window
+func (m *Model) loadNewerLogs(follow bool) tea.Cmd {
+	if m.logsLoading || m.logs.Len() == 0 {
+		return nil
+	}
+	m.logsLoading = true
+
+	gen, query := m.logGen, m.logQuery()
+	cursor := m.logs.At(m.logs.Len() - 1).Cursor
+	return func() tea.Msg {
+		logs, err := m.logReader.GetLogsAfter(query, cursor, logPageSize)
+		return logsAppendedMsg{
+			gen:    gen,
+			logs:   logs,
+			atTail: len(logs) < logPageSize,
+			follow: follow,
+			err:    err,
+		}
+	}
+}
+
+// loadOlderLogs loads the page before the oldest entry in the window
+func (m *Model) loadOlderLogs() tea.Cmd {
+	if m.logsLoading || m.logsAtHead || m.logs.Len() == 0 {
+		return nil
+	}
+	m.logsLoading = true
+
+	gen, query := m.logGen, m.logQuery()
+	cursor := m.logs.At(0).Cursor
+	return func() tea.Msg {
+		logs, err := m.logReader.GetLogsBefore(query, cursor, logPageSize)
+		return logsPrependedMsg{
+			gen:    gen,
+			logs:   logs,
+			atHead: len(logs) < logPageSize,
+			err:    err,
+		}
+	}
+}
+
+// handleLogsLoaded replaces the window with a fresh page
+func (m *Model) handleLogsLoaded(msg logsLoadedMsg) {
+	if msg.gen != m.logGen {
+		return
+	}
+	m.logsLoading = false
+	if msg.err != nil {
+		m.errMsg = "Failed to read logs: " + msg.err.Error()
+		return
+	}
+
+	m.logs.Reset(msg.logs)
+	m.logsAtHead = len(msg.logs) < logPageSize
+	m.logsAtTail = true
+	m.search.index(m.logs)
+
+	if !m.showProcessTree {
+		m.refreshLogView(true)
+	}
+}
+
+// handleLogsAppended adds newer entries, evicting the oldest ones when the
+// buffer is full while keeping the visible lines in place
+func (m *Model) handleLogsAppended(msg logsAppendedMsg) {
+	if msg.gen != m.logGen {
+		return
+	}
+	m.logsLoading = false
+	if msg.err != nil {
+		m.errMsg = "Failed to read logs: " + msg.err.Error()
+		return
+	}
+
+	m.logsAtTail = msg.atTail
+	if len(msg.logs) == 0 {
+		return
+	}
+
+	// Lines that scroll out of the buffer at the top
+	removedLines := 0
+	if overflow := m.logs.Len() + len(msg.logs) - m.logs.Cap(); overflow > 0 {
+		removedLines = m.logLine(min(overflow, m.logs.Len()))
+	}
+
+	wasAtBottom := m.logViewport.AtBottom()
+	offset := m.logViewport.YOffset
+
+	if m.logs.Append(msg.logs) > 0 {
+		m.logsAtHead = false
+	}
+	m.search.index(m.logs)
+
+	if m.showProcessTree {
+		return
+	}
+	if msg.follow && wasAtBottom {
+		m.refreshLogView(true)
+		return
+	}
+	m.logViewport.SetContent(m.formatLogs())
+	m.logViewport.SetYOffset(offset - removedLines)
+}
+
+// handleLogsPrepended adds older entries above the window, evicting the
+// newest ones when the buffer is full, and keeps the scroll position on
+// the lines that were visible
+func (m *Model) handleLogsPrepended(msg logsPrependedMsg) {
+	if msg.gen != m.logGen {
+		return
+	}
+	m.logsLoading = false
+	if msg.err != nil {
+		m.errMsg = "Failed to read logs: " + msg.err.Error()
+		return
+	}
+
+	m.logsAtHead = msg.atHead
+	if len(msg.logs) == 0 {
+		return
+	}
+
+	addedLines := entryLines(msg.logs)
+	offset := m.logViewport.YOffset
+
+	if m.logs.Prepend(msg.logs) > 0 {
+		m.logsAtTail = false
+	}
+	m.search.index(m.logs)
+
+	if m.showProcessTree {
+		return
+	}
+	m.logViewport.SetContent(m.formatLogs())
+	m.logViewport.SetYOffset(offset + addedLines)
+}
+
+// scrollLogs scrolls the logs pane, paging in older or newer entries when
+// scrolling past either end of the window
+func (m *Model) scrollLogs(msg tea.KeyMsg) tea.Cmd {
+	wasAtTop := m.logViewport.AtTop()
+	wasAtBottom := m.logViewport.AtBottom()
+
+	var cmd tea.Cmd
+	m.logViewport, cmd = m.logViewport.Update(msg)
+
+	if m.showProcessTree {
+		return cmd
+	}
+
+	switch msg.String() {
+	case "up", "k", "pgup", "ctrl+u":
+		if wasAtTop {
+			return tea.Batch(cmd, m.loadOlderLogs())
+		}
+	case "down", "j", "pgdown", "ctrl+d":
+		if wasAtBottom && !m.logsAtTail {
+			return tea.Batch(cmd, m.loadNewerLogs(false))
+		}
+	}
+	return cmd
+}
+
+// scrollLogsMouse pages in entries when the wheel scrolls past either end
+func (m *Model) scrollLogsMouse(msg tea.MouseMsg) tea.Cmd {
+	if m.showProcessTree {
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		if m.logViewport.AtTop() {
+			return m.loadOlderLogs()
+		}
+	case tea.MouseWheelDown:
+		if m.logViewport.AtBottom() && !m.logsAtTail {
+			return m.loadNewerLogs(false)
+		}
+	}
+	return nil
+}
+
+// entryLines counts the viewport lines taken by log entries
+func entryLines(logs []types.LogEntry) int {
+	lines := 0
+	for _, log := range logs {
+		lines += 1 + strings.Count(log.Message, "\n")
+	}
+	return lines
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logSearch holds the state of a search within the logs pane
+type logSearch struct {
+	input   textinput.Model
+	typing  bool           // Prompt is open and receiving keys
+	regex   bool           // Treat the query as a regular expression
+	query   string         // Confirmed query
+	pattern *regexp.Regexp // Compiled query, nil when no search is active
+	matches []int          // Indices of log entries containing a match
+	current int            // Index into matches of the focused match
+
+	focusCursor string // Journal cursor of the focused match's entry
+}
+
+// newLogSearch creates an empty log search
+func newLogSearch() logSearch {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.CharLimit = 256
+	return logSearch{input: input}
+}
+
+// active reports whether a confirmed search is highlighting matches
+func (s *logSearch) active() bool {
+	return s.pattern != nil
+}
+
+// open shows the search prompt, prefilled with the current query
+func (s *logSearch) open() tea.Cmd {
+	s.typing = true
+	s.input.SetValue(s.query)
+	s.input.CursorEnd()
+	s.updatePrompt()
+	return s.input.Focus()
+}
+
+// clear removes the active search
+func (s *logSearch) clear() {
+	s.typing = false
+	s.query = ""
+	s.pattern = nil
+	s.matches = nil
+	s.current = 0
+	s.focusCursor = ""
+	s.input.Blur()
+}
+
+// toggleRegex switches between plain text and regex matching
+func (s *logSearch) toggleRegex() {
+	s.regex = !s.regex
+	s.updatePrompt()
+}
+
+// updatePrompt shows the matching mode in the prompt
+func (s *logSearch) updatePrompt() {
+	if s.regex {
+		s.input.Prompt = "regex /"
+	} else {
+		s.input.Prompt = "search /"
+	}
+}
+
+// confirm compiles the typed query and closes the prompt
+func (s *logSearch) confirm() error {
+	s.typing = false
+	s.input.Blur()
+
+	query := s.input.Value()
+	if query == "" {
+		s.clear()
+		return nil
+	}
+
+	var expr string
+	if s.regex {
+		expr = query
+	} else {
+		expr = "(?i)" + regexp.QuoteMeta(query)
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid regex: %w", err)
+	}
+
+	s.query = query
+	s.pattern = pattern
+	s.current = 0
+	s.focusCursor = ""
+	return nil
+}
+
+// index recomputes which log entries match the search. The focused match
+// follows its entry (by journal cursor) as lines stream in or scroll out.
+func (s *logSearch) index(logs *logBuffer) {
+	s.matches = s.matches[:0]
+	if s.pattern == nil {
+		return
+	}
+
+	s.current = -1
+	for i := 0; i < logs.Len(); i++ {
+		log := logs.At(i)
+		if !s.matchesText(log.Message) {
+			continue
+		}
+		if log.Cursor == s.focusCursor {
+			s.current = len(s.matches)
+		}
+		s.matches = append(s.matches, i)
+	}
+
+	// The focused entry is gone; fall back to the most recent match
+	if s.current < 0 {
+		s.focusLatest(logs)
+	}
+}
+
+// focusLatest focuses the most recent match
+func (s *logSearch) focusLatest(logs *logBuffer) {
+	s.current = len(s.matches) - 1
+	if s.current < 0 {
+		s.current = 0
+		s.focusCursor = ""
+		return
+	}
+	s.focusCursor = logs.At(s.matches[s.current]).Cursor
+}
+
+// next moves to the next (or previous) match, wrapping around
+func (s *logSearch) next(forward bool, logs *logBuffer) {
+	if len(s.matches) == 0 {
+		return
+	}
+	if forward {
+		s.current = (s.current + 1) % len(s.matches)
+	} else {
+		s.current = (s.current - 1 + len(s.matches)) % len(s.matches)
+	}
+	s.focusCursor = logs.At(s.matches[s.current]).Cursor
+}
+
+// currentEntry returns the log index of the focused match, or -1
+func (s *logSearch) currentEntry() int {
+	if !s.active() || s.current >= len(s.matches) {
+		return -1
+	}
+	return s.matches[s.current]
+}
+
+// status describes the match position for the logs title bar
+func (s *logSearch) status() string {
+	if !s.active() {
+		return ""
+	}
+	if len(s.matches) == 0 {
+		return fmt.Sprintf("/%s: no matches", s.query)
+	}
+	return fmt.Sprintf("/%s: %d/%d", s.query, s.current+1, len(s.matches))
+}
+
+// matchesText reports whether the pattern matches some of text; empty
+// matches (e.g. "a*" on any text) don't count, since highlight has
+// nothing to show for them
+func (s *logSearch) matchesText(text string) bool {
+	for _, loc := range s.pattern.FindAllStringIndex(text, -1) {
+		if loc[0] < loc[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight renders text with every search match emphasized
+func (s *logSearch) highlight(text string, base, match lipgloss.Style) string {
+	if s.pattern == nil {
+		return base.Render(text)
+	}
+
+	locs := s.pattern.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return base.Render(text)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range locs {
+		if loc[0] == loc[1] {
+			// Skip empty matches (e.g. "a*")
+			continue
+		}
+		sb.WriteString(base.Render(text[last:loc[0]]))
+		sb.WriteString(match.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	sb.WriteString(base.Render(text[last:]))
+	return sb.String()
+}
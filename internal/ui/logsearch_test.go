@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"sdtop/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchFor confirms a query in a new search
+func searchFor(t *testing.T, query string, regex bool) logSearch {
+	t.Helper()
+	s := newLogSearch()
+	s.regex = regex
+	s.input.SetValue(query)
+	if err := s.confirm(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestLogSearchIndex(t *testing.T) {
+	logs := newLogBuffer(10)
+	var entries []types.LogEntry
+	for i, msg := range []string{"Started nginx", "connection reset", "NGINX reloaded", "", "bbb"} {
+		entries = append(entries, types.LogEntry{Message: msg, Cursor: fmt.Sprint(i)})
+	}
+	logs.Reset(entries)
+
+	tests := []struct {
+		query string
+		regex bool
+		want  []int
+	}{
+		{"nginx", false, []int{0, 2}},
+		{"a.c", false, []int{}},
+		{"re(set|loaded)", true, []int{1, 2}},
+		// Zero-length matches don't count, as highlight has nothing to show
+		{"a*", true, []int{0, 2}},
+		{"x?", true, []int{0}},
+		{"^", true, []int{}},
+	}
+
+	for _, tt := range tests {
+		s := searchFor(t, tt.query, tt.regex)
+		s.index(logs)
+		if got := append([]int{}, s.matches...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: matches %v, want %v", tt.query, got, tt.want)
+		}
+		// The most recent match is focused
+		if len(tt.want) > 0 && s.current != len(tt.want)-1 {
+			t.Errorf("%q: focused match %d, want %d", tt.query, s.current, len(tt.want)-1)
+		}
+	}
+}
+
+func TestLogSearchHighlight(t *testing.T) {
+	base := lipgloss.NewStyle()
+	match := lipgloss.NewStyle()
+
+	tests := []struct {
+		query string
+		regex bool
+		text  string
+	}{
+		{"nginx", false, "Started NGINX and nginx"},
+		{"a*", true, "bad apple"},
+		{"^", true, "anything"},
+	}
+
+	for _, tt := range tests {
+		s := searchFor(t, tt.query, tt.regex)
+		// Plain styles render text unchanged, so highlighting must keep
+		// every character exactly once
+		if got := s.highlight(tt.text, base, match); got != tt.text {
+			t.Errorf("%q on %q: rendered %q", tt.query, tt.text, got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toggleMark marks or unmarks the highlighted service for the merged view
+func (m *Model) toggleMark() tea.Cmd {
+	item, ok := m.serviceList.SelectedItem().(serviceItem)
+	if !ok {
+		return nil
+	}
+
+	name := item.service.Name
+	if m.marked[name] {
+		delete(m.marked, name)
+	} else {
+		m.marked[name] = true
+	}
+	return m.refreshServiceItem(item.service)
+}
+
+// openMergedLogs shows the logs of all marked services in one stream
+func (m *Model) openMergedLogs() tea.Cmd {
+	if len(m.marked) == 0 {
+		return func() tea.Msg {
+			return statusMsgType("Mark services with space first")
+		}
+	}
+
+	units := make([]string, 0, len(m.marked))
+	for name := range m.marked {
+		units = append(units, name)
+	}
+	sort.Strings(units)
+
+	m.currentService = ""
+	m.logUnits = units
+	m.merged = true
+	m.muted = map[string]bool{}
+	m.logPIDs = nil
+	m.showProcessTree = false
+	m.resetLogs()
+
+	return m.refreshLogs()
+}
+
+// toggleMute hides or shows the n-th source (1-based) of the merged view
+func (m *Model) toggleMute(n int) tea.Cmd {
+	if n < 1 || n > len(m.logUnits) {
+		return nil
+	}
+
+	unit := m.logUnits[n-1]
+	m.muted[unit] = !m.muted[unit]
+	return m.refreshLogs()
+}
+
+// activeLogUnits returns the units whose logs are shown, minus muted ones
+func (m *Model) activeLogUnits() []string {
+	var units []string
+	for _, unit := range m.logUnits {
+		if !m.muted[unit] {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+// unitTagWidth is the width of the widest unit tag in the merged view
+func (m *Model) unitTagWidth() int {
+	width := 0
+	for _, unit := range m.logUnits {
+		width = max(width, len(shortUnitName(unit)))
+	}
+	return width
+}
+
+// renderUnitTag renders the colored source prefix of a merged log line
+func (m *Model) renderUnitTag(entry types.LogEntry, width int) string {
+	for i, unit := range m.logUnits {
+		if unit == entry.Unit {
+			return lipgloss.NewStyle().
+				Foreground(m.theme.tagColor(i)).
+				Bold(true).
+				Render(fmt.Sprintf("%-*s", width, shortUnitName(unit)))
+		}
+	}
+	return strings.Repeat(" ", width)
+}
+
+// renderMergedSources lists the merged units with their mute toggles
+func (m *Model) renderMergedSources() string {
+	var parts []string
+	for i, unit := range m.logUnits {
+		style := lipgloss.NewStyle().Foreground(m.theme.tagColor(i)).Bold(true)
+		if m.muted[unit] {
+			style = lipgloss.NewStyle().Foreground(m.theme.Muted).Strikethrough(true)
+		}
+		parts = append(parts, style.Render(fmt.Sprintf("%d:%s", i+1, shortUnitName(unit))))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shortUnitName drops the .service suffix for compact display
+func shortUnitName(unit string) string {
+	return strings.TrimSuffix(unit, ".service")
+}
@@ -6,9 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"sdtop/internal/export"
 	"sdtop/internal/systemd"
 	"sdtop/internal/types"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,32 +19,114 @@ import (
 
 // Model is the Bubble Tea model for the TUI
 type Model struct {
-	serviceList     list.Model
-	logViewport     viewport.Model
-	services        []types.Service
-	allServices     []types.Service // Keep unfiltered list
-	currentService  string
-	logs            []types.LogEntry
-	processes       []*types.Process
-	manager         *systemd.Manager
-	logReader       *systemd.LogReader
-	processManager  *systemd.ProcessManager
-	logCancel       context.CancelFunc
-	statusMsg       string
-	errMsg          string
-	width           int
-	height          int
-	ready           bool
-	filterMode      string // "all", "running", "failed"
-	showProcessTree bool   // Toggle between logs and process tree
+	serviceList       list.Model
+	logViewport       viewport.Model
+	services          []types.Service
+	allServices       []types.Service // Keep unfiltered list
+	currentService    string
+	logUnits          []string        // Units whose logs are shown
+	merged            bool            // Showing a merged multi-unit stream
+	marked            map[string]bool // Services marked for the merged view
+	muted             map[string]bool // Merged sources hidden from the stream
+	logs              *logBuffer
+	processes         []*types.Process
+	manager           *systemd.Manager
+	logReader         *systemd.LogReader
+	processManager    *systemd.ProcessManager
+	logCancel         context.CancelFunc
+	statusMsg         string
+	errMsg            string
+	width             int
+	height            int
+	ready             bool
+	filterMode        string // "all", "running", "failed", "flapping"
+	showProcessTree   bool   // Toggle between logs and process tree
+	theme             *Theme
+	focus             focusPane
+	search            logSearch
+	maxPriority       types.Priority // Least severe log level shown
+	prompt            prompt
+	boots             []types.Boot
+	boot              *types.Boot // nil shows all boots
+	bootSpec          string      // Boot requested on the command line
+	since             time.Time
+	until             time.Time
+	logGen            int             // Generation of the current log query
+	logsLoading       bool            // A log page request is in flight
+	logsAtHead        bool            // No older entries left in range
+	logsAtTail        bool            // Newest entry in range is loaded
+	selectedCursor    string          // Journal cursor of the selected log entry
+	detail            *types.LogEntry // Entry shown in the field popup
+	detailViewport    viewport.Model
+	selectedPID       int          // Highlighted process in the process tree
+	expandedPIDs      map[int]bool // Processes whose threads are shown
+	processesLoadedAt time.Time
+	logPIDs           []int  // Limit logs to these processes (nil shows all)
+	showCatalog       bool   // Show catalog explanations next to the logs
+	catalogCursor     string // Entry the catalog text belongs to
+	catalogText       string
+	statsOpen         bool            // Statistics overlay is shown
+	stats             *types.LogStats // nil while loading
+	triage            *triageView     // Failed-unit triage screen, nil when closed
+	flaps             *systemd.FlapDetector
+	coredumps         *coredumpView      // Coredump browser, nil when closed
+	deps              *depView           // Dependency tree, nil when closed
+	analysis          *analysisView      // Boot analysis overlay, nil when closed
+	security          *securityView      // Security review overlay, nil when closed
+	lint              *lintView          // Unit file lint panel, nil when closed
+	limits            *limitsView        // Resource limits form, nil when closed
+	run               *runView           // Transient unit form, nil when closed
+	environment       *envView           // Environment viewer, nil when closed
+	sockets           *socketView        // Sockets or port lookup, nil when closed
+	jumpRange         *timeRange         // Range to restore after viewing a crash window
+	pendingSelect     string             // Unit to highlight once the list has it
+	exposure          map[string]float64 // Exposure score by service, nil until scored
+	sortByExposure    bool               // List the most exposed services first
+	scoringExposure   bool               // A scoring pass is running
 }
 
+// Options configures the UI at startup
+type Options struct {
+	Theme *Theme
+
+	// Boot selects the boot to show logs for ("all", "0", "-1" or an ID)
+	Boot string
+
+	// Since and Until limit the log time range (zero means unbounded)
+	Since time.Time
+	Until time.Time
+
+	// LogBufferSize caps the log entries kept in memory
+	LogBufferSize int
+
+	// FlapStarts and FlapWindow flag services starting more than
+	// FlapStarts times within FlapWindow
+	FlapStarts int
+	FlapWindow time.Duration
+}
+
+// focusPane identifies which pane receives navigation keys
+type focusPane int
+
+const (
+	focusList focusPane = iota
+	focusLogs
+)
+
 // serviceItem wraps a service for the list
 type serviceItem struct {
-	service types.Service
+	service  types.Service
+	theme    *Theme
+	marked   bool // Included in the merged log view
+	flapping bool // Restarting too often
+	exposure float64
+	scored   bool // exposure is known
 }
 
 func (i serviceItem) Title() string {
+	if i.marked {
+		return "◆ " + i.service.Name
+	}
 	return i.service.Name
 }
 
@@ -53,32 +137,7 @@ func (i serviceItem) Description() string {
 		state = i.service.ActiveState
 	}
 
-	var stateColor lipgloss.Color
-	var stateSymbol string
-
-	switch state {
-	case "running":
-		stateColor = lipgloss.Color("42") // Green
-		stateSymbol = "●"
-	case "exited":
-		stateColor = lipgloss.Color("240") // Gray
-		stateSymbol = "○"
-	case "failed":
-		stateColor = lipgloss.Color("196") // Red
-		stateSymbol = "✗"
-	case "dead":
-		stateColor = lipgloss.Color("240") // Gray
-		stateSymbol = "○"
-	case "active":
-		stateColor = lipgloss.Color("42") // Green
-		stateSymbol = "●"
-	case "inactive":
-		stateColor = lipgloss.Color("240") // Gray
-		stateSymbol = "○"
-	default:
-		stateColor = lipgloss.Color("226") // Yellow
-		stateSymbol = "◐"
-	}
+	stateColor, stateSymbol := i.theme.stateStyle(state)
 
 	styledState := lipgloss.NewStyle().
 		Foreground(stateColor).
@@ -93,17 +152,68 @@ func (i serviceItem) Description() string {
 	bootStatus := ""
 	if i.service.LoadState == "loaded" {
 		if strings.Contains(i.service.UnitFileState, "enabled") {
-			bootStatus = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(" [boot]")
+			bootStatus = lipgloss.NewStyle().Foreground(i.theme.Success).Render(" [boot]")
 		}
 	}
 
-	return fmt.Sprintf("%s %s%s", styledState, desc, bootStatus)
+	// Flag crash loops before anything else
+	flapBadge := ""
+	if i.flapping {
+		flapBadge = lipgloss.NewStyle().Foreground(i.theme.Error).Bold(true).Render("⟳ flapping ")
+	}
+
+	// Show the security exposure once services are scored
+	exposure := ""
+	if i.scored {
+		exposure = lipgloss.NewStyle().Foreground(i.theme.exposureColor(i.exposure)).
+			Render(fmt.Sprintf(" ⛨%.1f", i.exposure))
+	}
+
+	return fmt.Sprintf("%s%s%s %s%s", flapBadge, styledState, exposure, desc, bootStatus)
 }
 
 func (i serviceItem) FilterValue() string {
 	return i.service.Name
 }
 
+// newServiceItem wraps a service with its current marks and badges
+func (m *Model) newServiceItem(svc types.Service) serviceItem {
+	exposure, scored := m.exposure[svc.Name]
+	return serviceItem{
+		service:  svc,
+		theme:    m.theme,
+		marked:   m.marked[svc.Name],
+		flapping: m.flaps.Flapping(svc.Name),
+		exposure: exposure,
+		scored:   scored,
+	}
+}
+
+// refreshServiceItem rebuilds the list row of a service, if it's listed.
+// The list's own index is a position among the rows a list filter shows,
+// so the row is looked up by name among all items.
+func (m *Model) refreshServiceItem(svc types.Service) tea.Cmd {
+	for i, item := range m.serviceList.Items() {
+		if s, ok := item.(serviceItem); ok && s.service.Name == svc.Name {
+			return m.serviceList.SetItem(i, m.newServiceItem(svc))
+		}
+	}
+	return nil
+}
+
+// scrollPanels keeps the open panels' scroll positions valid after the
+// screen is resized
+func (m *Model) scrollPanels() {
+	m.scrollTriage()
+	m.scrollCoredumps()
+	m.scrollDeps()
+	m.scrollAnalysis()
+	m.scrollSecurity()
+	m.scrollLint()
+	m.scrollEnvironment()
+	m.scrollSockets()
+}
+
 // tickMsg is sent periodically to update logs
 type tickMsg time.Time
 
@@ -114,9 +224,11 @@ type logUpdateMsg struct{}
 type statusMsgType string
 
 // NewModel creates a new UI model
-func NewModel(manager *systemd.Manager, logReader *systemd.LogReader) (*Model, error) {
+func NewModel(manager *systemd.Manager, logReader *systemd.LogReader, opts Options) (*Model, error) {
+	theme := opts.Theme
+
 	// Create list
-	delegate := list.NewDefaultDelegate()
+	delegate := theme.listDelegate()
 	serviceList := list.New([]list.Item{}, delegate, 0, 0)
 	serviceList.Title = "SYSTEMD SERVICES"
 	serviceList.SetShowStatusBar(false)
@@ -125,8 +237,8 @@ func NewModel(manager *systemd.Manager, logReader *systemd.LogReader) (*Model, e
 	// Customize list styles
 	serviceList.Styles.Title = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("170")).
-		Background(lipgloss.Color("235")).
+		Foreground(theme.Accent).
+		Background(theme.Background).
 		Padding(0, 1)
 
 	return &Model{
@@ -134,8 +246,20 @@ func NewModel(manager *systemd.Manager, logReader *systemd.LogReader) (*Model, e
 		manager:        manager,
 		logReader:      logReader,
 		processManager: systemd.NewProcessManager(),
-		logs:           []types.LogEntry{},
+		expandedPIDs:   map[int]bool{},
+		logs:           newLogBuffer(max(opts.LogBufferSize, 2*logPageSize)),
+		marked:         map[string]bool{},
+		muted:          map[string]bool{},
 		filterMode:     "all",
+		theme:          theme,
+		search:         newLogSearch(),
+		maxPriority:    types.PriorityDebug,
+		showCatalog:    true,
+		flaps:          systemd.NewFlapDetector(opts.FlapStarts, opts.FlapWindow),
+		prompt:         newPrompt(),
+		bootSpec:       opts.Boot,
+		since:          opts.Since,
+		until:          opts.Until,
 	}, nil
 }
 
@@ -143,6 +267,8 @@ func NewModel(manager *systemd.Manager, logReader *systemd.LogReader) (*Model, e
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadServices,
+		m.loadBoots,
+		m.watchStates,
 		m.tickCmd(),
 	)
 }
@@ -154,18 +280,12 @@ func (m *Model) loadServices() tea.Msg {
 		return systemd.ErrorMsg(fmt.Sprintf("Failed to list services: %v", err))
 	}
 
-	items := make([]list.Item, len(services))
-	for i, svc := range services {
-		items[i] = serviceItem{service: svc}
-	}
-
-	return servicesLoadedMsg{services: services, items: items}
+	return servicesLoadedMsg{services: services}
 }
 
 // servicesLoadedMsg is sent when services are loaded
 type servicesLoadedMsg struct {
 	services []types.Service
-	items    []list.Item
 }
 
 // tickCmd creates a ticker for log updates
@@ -181,6 +301,62 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Open prompts take all keys
+		if m.search.typing {
+			return m, m.updateSearchInput(msg)
+		}
+		if m.prompt.active() {
+			return m, m.updatePrompt(msg)
+		}
+		if m.detail != nil {
+			return m, m.updateLogDetail(msg)
+		}
+		if m.statsOpen {
+			return m, m.updateStats(msg)
+		}
+		if m.analysis != nil {
+			return m, m.updateAnalysis(msg)
+		}
+		if m.security != nil {
+			return m, m.updateSecurity(msg)
+		}
+		if m.lint != nil {
+			return m, m.updateLint(msg)
+		}
+		if m.limits != nil {
+			return m, m.updateLimits(msg)
+		}
+		if m.run != nil {
+			return m, m.updateRun(msg)
+		}
+		if m.environment != nil {
+			return m, m.updateEnvironment(msg)
+		}
+		if m.sockets != nil {
+			return m, m.updateSockets(msg)
+		}
+		if m.triage != nil {
+			return m, m.updateTriage(msg)
+		}
+		if m.coredumps != nil {
+			return m, m.updateCoredumps(msg)
+		}
+		if m.deps != nil {
+			return m, m.updateDeps(msg)
+		}
+
+		// Digits mute merged sources while the merged logs are focused
+		if key := msg.String(); m.merged && m.focus == focusLogs && len(key) == 1 && key >= "1" && key <= "9" {
+			return m, m.toggleMute(int(key[0] - '0'))
+		}
+
+		// Let the list filter receive text without triggering actions
+		if m.serviceList.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.serviceList, cmd = m.serviceList.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			if m.logCancel != nil {
@@ -188,7 +364,229 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Quit
 
+		case "tab":
+			// Switch focus between the service list and logs
+			if m.focus == focusList && len(m.logUnits) > 0 {
+				m.focus = focusLogs
+				if !m.showProcessTree && m.selectedLog() < 0 {
+					m.selectLog(m.lastVisibleLog())
+				}
+			} else {
+				m.focus = focusList
+			}
+			// Redraw the selection marker
+			if m.showingLogs() {
+				m.logViewport.SetContent(m.formatLogs())
+			} else if m.showProcessTree {
+				m.logViewport.SetContent(m.formatProcessTree())
+			}
+			return m, m.syncCatalog()
+
+		case "/":
+			// Search logs when the logs pane is focused
+			if m.focus == focusLogs && !m.showProcessTree {
+				return m, m.search.open()
+			}
+
+		case "n", "N":
+			// Jump between search matches
+			if m.focus == focusLogs && m.search.active() {
+				m.search.next(msg.String() == "n", m.logs)
+				m.refreshLogView(false)
+				return m, nil
+			}
+
+		case "esc":
+			// Clear the log search, then the PID filter
+			if m.focus == focusLogs && m.search.active() {
+				m.search.clear()
+				m.refreshLogView(false)
+				return m, nil
+			}
+			if m.focus == focusLogs && m.showingLogs() && len(m.logPIDs) > 0 {
+				m.logPIDs = nil
+				m.resetLogs()
+				return m, m.refreshLogs()
+			}
+
+		case "v", "V":
+			// Raise (v) or lower (V) the minimum log priority shown
+			if m.showingLogs() {
+				if msg.String() == "v" && m.maxPriority > types.PriorityEmerg {
+					m.maxPriority--
+				} else if msg.String() == "V" && m.maxPriority < types.PriorityDebug {
+					m.maxPriority++
+				}
+				return m, m.refreshLogs()
+			}
+
+		case "b":
+			// Cycle through boots
+			if m.showingLogs() {
+				return m, m.cycleBoot()
+			}
+
+		case "T":
+			// Seek the logs to a point in time
+			if m.showingLogs() {
+				value := ""
+				if !m.until.IsZero() {
+					value = m.until.Format("2006-01-02 15:04:05")
+				}
+				return m, m.prompt.open(promptSeekTime, "show logs until: ", value,
+					"e.g. 14:30, yesterday, -2h, 2024-05-01 13:00 • empty follows the tail")
+			}
+
+		case "c":
+			// Toggle the catalog side panel
+			if m.focus == focusLogs && m.showingLogs() {
+				m.showCatalog = !m.showCatalog
+				return m, m.syncCatalog()
+			}
+
+		case "F":
+			// Triage failed units
+			return m, m.openTriage()
+
+		case "C":
+			// Browse coredumps
+			return m, m.openCoredumps()
+
+		case "D":
+			// Browse the current unit's dependencies
+			if m.currentService != "" {
+				return m, m.openDeps(m.serviceDependency(m.currentService), false)
+			}
+
+		case "i":
+			// Show log rate statistics
+			if m.showingLogs() {
+				return m, m.openStats()
+			}
+
+		case "x":
+			// Export the displayed logs to a file
+			if m.showingLogs() {
+				return m, m.prompt.open(promptExport, "export logs to: ", m.exportFileName(),
+					".log text • .json JSON lines • .export journal export format")
+			}
+
+		case "S":
+			// Bundle the service's state into an archive
+			if m.currentService != "" {
+				return m, m.prompt.open(promptSnapshot, "save snapshot to: ",
+					export.DefaultFileName("sdtop-snapshot", m.currentService, ".tar.gz"),
+					"status, properties, unit file, processes and recent logs")
+			}
+
+		case "X":
+			// Review the service's sandboxing
+			if m.currentService != "" {
+				return m, m.openSecurity()
+			}
+
+		case "E":
+			// Compare the configured and actual environment
+			if m.currentService != "" {
+				return m, m.openEnvironment()
+			}
+
+		case "O":
+			// Open sockets and listening ports of the service
+			if m.currentService != "" {
+				return m, m.openSockets()
+			}
+
+		case "W":
+			// Find who listens on a port
+			return m, m.promptPort()
+
+		case "!":
+			// Run a command as a transient unit
+			return m, m.openRun()
+
+		case "R":
+			// Change the service's resource limits while it runs
+			if m.currentService != "" {
+				return m, m.openLimits()
+			}
+
+		case "L":
+			// Lint the service's unit file and drop-ins
+			if m.currentService != "" {
+				return m, m.openLint()
+			}
+
+		case "o":
+			// Sort services by security exposure
+			return m, m.toggleExposureSort()
+
+		case "A":
+			// Analyze boot performance
+			return m, m.openAnalysis()
+
+		case "G":
+			// Export the service's dependency graph
+			if m.currentService != "" {
+				return m, m.promptGraph()
+			}
+
+		case "up", "k", "down", "j":
+			// Move the log or process selection
+			if m.focus == focusLogs {
+				delta := 1
+				if msg.String() == "up" || msg.String() == "k" {
+					delta = -1
+				}
+				if m.showProcessTree {
+					m.moveProcessSelection(delta)
+					return m, nil
+				}
+				return m, m.moveLogSelection(delta)
+			}
+
+		case "+":
+			// Show logs of the highlighted process and its descendants
+			if m.focus == focusLogs && m.showProcessTree {
+				return m, m.filterLogsToProcess(true)
+			}
+
+		case "y":
+			// Copy the selected log entry
+			if m.focus == focusLogs && !m.showProcessTree {
+				if idx := m.selectedLog(); idx >= 0 {
+					return m, copyLogEntry(m.logs.At(idx))
+				}
+				return m, nil
+			}
+
+		case " ":
+			// Show or hide the threads of the highlighted process
+			if m.focus == focusLogs && m.showProcessTree {
+				return m, m.toggleThreads()
+			}
+
+			// Mark the highlighted service for the merged log view
+			if m.focus == focusList {
+				return m, m.toggleMark()
+			}
+
+		case "M":
+			// Open the merged log view of all marked services
+			return m, m.openMergedLogs()
+
 		case "enter":
+			// Show all fields of the selected log entry
+			if m.focus == focusLogs && !m.showProcessTree {
+				m.openLogDetail()
+				return m, nil
+			}
+
+			// Show logs of the highlighted process
+			if m.focus == focusLogs && m.showProcessTree {
+				return m, m.filterLogsToProcess(false)
+			}
+
 			// Select service
 			if item, ok := m.serviceList.SelectedItem().(serviceItem); ok {
 				return m, m.selectService(item.service.Name)
@@ -243,6 +641,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.filterMode = "failed"
 			return m, m.applyFilter()
 
+		case "4":
+			// Show only flapping services
+			m.filterMode = "flapping"
+			return m, m.applyFilter()
+
 		case "p":
 			// Toggle process tree view
 			if m.currentService != "" {
@@ -250,6 +653,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.showProcessTree {
 					return m, m.loadProcessTree()
 				}
+				m.refreshLogView(true)
 			}
 			return m, nil
 
@@ -257,19 +661,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Back to logs view (if in process tree)
 			if m.showProcessTree {
 				m.showProcessTree = false
+				m.refreshLogView(true)
 			}
 			return m, nil
 
-		case "up", "k":
-			var cmd tea.Cmd
-			m.serviceList, cmd = m.serviceList.Update(msg)
-			return m, cmd
+		}
 
-		case "down", "j":
-			var cmd tea.Cmd
-			m.serviceList, cmd = m.serviceList.Update(msg)
-			return m, cmd
+		// Remaining keys go to the focused pane only
+		if m.focus == focusLogs {
+			return m, m.scrollLogs(msg)
 		}
+		var cmd tea.Cmd
+		m.serviceList, cmd = m.serviceList.Update(msg)
+		return m, cmd
+
+	case tea.MouseMsg:
+		// Scrolling past either end of the logs pages in more entries
+		cmds = append(cmds, m.scrollLogsMouse(msg))
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -284,19 +692,45 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.ready {
 			m.logViewport = viewport.New(rightWidth, msg.Height-4)
 			m.logViewport.YPosition = 0
+			m.logViewport.KeyMap = logViewportKeyMap()
 			m.ready = true
 		} else {
 			m.logViewport.Width = rightWidth
 			m.logViewport.Height = msg.Height - 4
 		}
+		m.scrollPanels()
 
 		return m, nil
 
+	case bootsLoadedMsg:
+		m.boots = msg.boots
+		if m.bootSpec != "" {
+			boot, err := systemd.ResolveBoot(m.boots, m.bootSpec)
+			if err != nil {
+				m.errMsg = err.Error()
+			}
+			m.boot = boot
+			m.bootSpec = ""
+		}
+		return m, nil
+
+	case stateWatchMsg:
+		return m, waitForStateChange(msg.changes)
+
+	case stateChangedMsg:
+		return m, m.handleStateChange(msg)
+
 	case servicesLoadedMsg:
-		m.services = msg.services
 		m.allServices = msg.services
-		m.serviceList.SetItems(msg.items)
-		return m, nil
+		cmd := m.applyFilter()
+		if m.pendingSelect != "" {
+			m.highlightService(m.pendingSelect)
+			if !m.hasService(m.pendingSelect) {
+				// Not listed at all, so a later reload won't have it either
+				m.pendingSelect = ""
+			}
+		}
+		return m, cmd
 
 	case systemd.ErrorMsg:
 		m.errMsg = string(msg)
@@ -314,25 +748,104 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
-		// Update logs only if NOT viewing process tree
-		if m.currentService != "" && !m.showProcessTree {
-			return m, tea.Batch(m.tickCmd(), m.refreshLogs())
+		// Overlays and the thread view refresh on their own schedule,
+		// whether or not the logs behind them are followed
+		cmds = append(cmds, m.tickCmd(), m.refreshLimits(), m.refreshThreads())
+
+		// Poll for new entries only if NOT viewing process tree, and only
+		// when following the tail of the journal
+		if m.showingLogs() && !m.logsLoading &&
+			m.logsAtTail && m.until.IsZero() {
+			cmds = append(cmds, m.followLogs())
 		}
-		return m, m.tickCmd()
+		return m, tea.Batch(cmds...)
 
 	case logsLoadedMsg:
-		// Only update if not viewing process tree
-		if !m.showProcessTree {
-			m.logs = msg.logs
-			m.logViewport.SetContent(m.formatLogs())
-			m.logViewport.GotoBottom()
+		m.handleLogsLoaded(msg)
+		return m, nil
+
+	case logsAppendedMsg:
+		m.handleLogsAppended(msg)
+		return m, nil
+
+	case logsPrependedMsg:
+		m.handleLogsPrepended(msg)
+		return m, nil
+
+	case socketsLoadedMsg:
+		m.handleSocketsLoaded(msg)
+		return m, nil
+
+	case envLoadedMsg:
+		m.handleEnvLoaded(msg)
+		return m, nil
+
+	case runStartedMsg:
+		return m, m.handleRunStarted(msg)
+
+	case limitsLoadedMsg:
+		return m, m.handleLimitsLoaded(msg)
+
+	case lintLoadedMsg:
+		m.handleLintLoaded(msg)
+		return m, nil
+
+	case securityLoadedMsg:
+		return m, m.handleSecurityLoaded(msg)
+
+	case exposureLoadedMsg:
+		m.exposure = msg.scores
+		m.scoringExposure = false
+		return m, m.applyFilter()
+
+	case analysisLoadedMsg:
+		m.handleAnalysisLoaded(msg)
+		return m, nil
+
+	case depsLoadedMsg:
+		m.handleDepsLoaded(msg)
+		return m, nil
+
+	case coredumpsLoadedMsg:
+		m.handleCoredumpsLoaded(msg)
+		return m, nil
+
+	case triageLoadedMsg:
+		return m, m.handleTriageLoaded(msg)
+
+	case statsLoadedMsg:
+		if msg.err != nil {
+			m.statsOpen = false
+			m.errMsg = fmt.Sprintf("Failed to compute log stats: %v", msg.err)
+			return m, nil
+		}
+		if m.statsOpen {
+			m.stats = msg.stats
+		}
+		return m, nil
+
+	case catalogLoadedMsg:
+		if msg.cursor == m.catalogCursor {
+			m.catalogText = msg.text
 		}
 		return m, nil
 
 	case processesLoadedMsg:
+		if msg.refresh && !m.showProcessTree {
+			return m, nil
+		}
 		m.processes = msg.processes
+		m.processesLoadedAt = time.Now()
+		for pid := range m.expandedPIDs {
+			if findProcess(m.processes, pid) == nil {
+				delete(m.expandedPIDs, pid)
+			}
+		}
+		m.keepProcessSelection()
 		m.logViewport.SetContent(m.formatProcessTree())
-		m.logViewport.GotoTop()
+		if !msg.refresh {
+			m.logViewport.GotoTop()
+		}
 		return m, nil
 	}
 
@@ -348,15 +861,51 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// selectService switches to viewing logs for a service
+// highlightService selects a unit in the list, or does once the list has
+// it
+func (m *Model) highlightService(name string) {
+	for i, svc := range m.services {
+		if svc.Name == name {
+			m.serviceList.Select(i)
+			m.pendingSelect = ""
+			return
+		}
+	}
+	m.pendingSelect = name
+}
+
+// hasService reports whether a unit is among the loaded services, whether
+// or not the filter shows it
+func (m *Model) hasService(name string) bool {
+	for _, svc := range m.allServices {
+		if svc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectService switches to viewing logs for a service, in the time range
+// that applied before any jump to a crash window
 func (m *Model) selectService(serviceName string) tea.Cmd {
+	m.restoreRange()
+	return m.openServiceLogs(serviceName)
+}
+
+// openServiceLogs switches to viewing logs for a service in the current
+// time range
+func (m *Model) openServiceLogs(serviceName string) tea.Cmd {
 	// Cancel previous log stream
 	if m.logCancel != nil {
 		m.logCancel()
 	}
 
 	m.currentService = serviceName
-	m.logs = []types.LogEntry{}
+	m.logUnits = []string{serviceName}
+	m.merged = false
+	m.muted = map[string]bool{}
+	m.logPIDs = nil
+	m.resetLogs()
 
 	// Create new context for log streaming
 	_, cancel := context.WithCancel(context.Background())
@@ -365,25 +914,38 @@ func (m *Model) selectService(serviceName string) tea.Cmd {
 	return m.refreshLogs()
 }
 
-// refreshLogs loads recent logs for the current service
-func (m *Model) refreshLogs() tea.Cmd {
-	return func() tea.Msg {
-		logs, err := m.logReader.GetRecentLogs(m.currentService, 100)
-		if err != nil {
-			return systemd.ErrorMsg(fmt.Sprintf("Failed to read logs: %v", err))
-		}
-		return logsLoadedMsg{logs: logs}
-	}
+// resetLogs clears the logs window before loading a new stream
+func (m *Model) resetLogs() {
+	m.logs.Reset(nil)
+	m.selectedCursor = ""
+	m.logViewport.SetContent("")
 }
 
-// logsLoadedMsg is sent when logs are loaded
-type logsLoadedMsg struct {
-	logs []types.LogEntry
+// showingLogs reports whether the right pane shows a log stream
+func (m *Model) showingLogs() bool {
+	return len(m.logUnits) > 0 && !m.showProcessTree
 }
 
-// processesLoadedMsg is sent when process tree is loaded
+// logQuery builds the journal query for the current service and filters
+func (m *Model) logQuery() systemd.LogQuery {
+	q := systemd.LogQuery{
+		Units:       m.activeLogUnits(),
+		MaxPriority: m.maxPriority,
+		Since:       m.since,
+		Until:       m.until,
+		PIDs:        m.logPIDs,
+	}
+	if m.boot != nil {
+		q.BootID = m.boot.ID
+	}
+	return q
+}
+
+// processesLoadedMsg is sent when process tree is loaded, or reloaded in
+// place when refresh is set
 type processesLoadedMsg struct {
 	processes []*types.Process
+	refresh   bool
 }
 
 // clearStatusMsg clears the status message
@@ -391,12 +953,24 @@ type clearStatusMsg struct{}
 
 // loadProcessTree loads the process tree for current service
 func (m *Model) loadProcessTree() tea.Cmd {
+	return m.reloadProcessTree(false)
+}
+
+// reloadProcessTree loads the process tree, keeping the scroll position
+// when refreshing
+func (m *Model) reloadProcessTree(refresh bool) tea.Cmd {
+	unit := m.currentService
+	// Threads are read only for the expanded processes
+	expanded := make(map[int]bool, len(m.expandedPIDs))
+	for pid := range m.expandedPIDs {
+		expanded[pid] = true
+	}
 	return func() tea.Msg {
-		processes, err := m.processManager.GetServiceProcesses(m.currentService)
+		processes, err := m.processManager.GetServiceProcesses(unit, expanded)
 		if err != nil {
 			return systemd.ErrorMsg(fmt.Sprintf("Failed to load processes: %v", err))
 		}
-		return processesLoadedMsg{processes: processes}
+		return processesLoadedMsg{processes: processes, refresh: refresh}
 	}
 }
 
@@ -458,81 +1032,204 @@ func (m *Model) cycleFilter() tea.Cmd {
 	case "running":
 		m.filterMode = "failed"
 	case "failed":
+		m.filterMode = "flapping"
+	case "flapping":
 		m.filterMode = "all"
 	}
 	return m.applyFilter()
 }
 
-// applyFilter applies the current filter mode
+// applyFilter shows the services matching the current filter mode. It
+// runs in Update rather than as a command, since the items read the
+// model's marks, flap states and exposure scores.
 func (m *Model) applyFilter() tea.Cmd {
-	return func() tea.Msg {
-		var filtered []types.Service
+	var filtered []types.Service
 
-		switch m.filterMode {
-		case "running":
-			for _, svc := range m.allServices {
-				if svc.SubState == "running" || svc.ActiveState == "active" {
-					filtered = append(filtered, svc)
-				}
-			}
-		case "failed":
-			for _, svc := range m.allServices {
-				if svc.ActiveState == "failed" || svc.SubState == "failed" {
-					filtered = append(filtered, svc)
-				}
+	switch m.filterMode {
+	case "running":
+		for _, svc := range m.allServices {
+			if svc.SubState == "running" || svc.ActiveState == "active" {
+				filtered = append(filtered, svc)
 			}
-		default: // "all"
-			filtered = m.allServices
 		}
-
-		items := make([]list.Item, len(filtered))
-		for i, svc := range filtered {
-			items[i] = serviceItem{service: svc}
+	case "failed":
+		for _, svc := range m.allServices {
+			if svc.ActiveState == "failed" || svc.SubState == "failed" {
+				filtered = append(filtered, svc)
+			}
 		}
+	case "flapping":
+		filtered = m.flappingServices()
+	default: // "all"
+		filtered = m.allServices
+	}
+	if m.sortByExposure && m.exposure != nil {
+		filtered = m.sortByExposureScore(filtered)
+	}
 
-		return servicesLoadedMsg{services: filtered, items: items}
+	items := make([]list.Item, len(filtered))
+	for i, svc := range filtered {
+		items[i] = m.newServiceItem(svc)
 	}
+
+	m.services = filtered
+	return m.serviceList.SetItems(items)
 }
 
 // formatLogs formats logs for display
 func (m *Model) formatLogs() string {
-	if len(m.logs) == 0 {
-		if m.currentService == "" {
+	if m.logs.Len() == 0 {
+		if len(m.logUnits) == 0 {
 			return m.renderEmptyState()
 		}
 		return m.renderNoLogsState()
 	}
 
+	matchStyle := lipgloss.NewStyle().Foreground(m.theme.Warning).Reverse(true)
+	currentMatchStyle := lipgloss.NewStyle().Foreground(m.theme.Accent).Reverse(true).Bold(true)
+	currentMatch := m.search.currentEntry()
+	selected := m.selectedLog()
+	gutter := lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▌")
+
+	tagWidth := m.unitTagWidth()
+
 	var sb strings.Builder
-	for _, log := range m.logs {
+	for i := 0; i < m.logs.Len(); i++ {
+		log := m.logs.At(i)
 		timestamp := log.Timestamp.Format("15:04:05")
 
 		// Color-code by priority
-		var lineStyle lipgloss.Style
-		priorityIcon := "  "
-
-		switch log.Priority {
-		case "error":
-			lineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-			priorityIcon = "✗ "
-		case "warn":
-			lineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("226"))
-			priorityIcon = "⚠ "
-		default:
-			lineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-			priorityIcon = "  "
-		}
+		lineStyle, priorityIcon := m.theme.priorityStyle(log.Priority)
 
 		timestampStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
+			Foreground(m.theme.Muted).
 			Render(timestamp)
 
-		line := fmt.Sprintf("%s %s%s\n", timestampStyle, priorityIcon, log.Message)
-		sb.WriteString(lineStyle.Render(line))
+		highlightStyle := matchStyle
+		if i == currentMatch {
+			highlightStyle = currentMatchStyle
+		}
+
+		// Mark the selected entry when the logs pane has focus
+		if i == selected && m.focus == focusLogs {
+			sb.WriteString(gutter)
+		} else {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(timestampStyle)
+		sb.WriteString(" ")
+		if m.merged {
+			sb.WriteString(m.renderUnitTag(log, tagWidth))
+			sb.WriteString(" ")
+		}
+		sb.WriteString(lineStyle.Render(priorityIcon))
+		sb.WriteString(m.theme.sourceMarker(log.Source))
+		sb.WriteString(m.search.highlight(log.Message, lineStyle, highlightStyle))
+		sb.WriteString("\n")
 	}
 	return sb.String()
 }
 
+// refreshLogView re-renders the logs pane, keeping the focused search
+// match in view or following the tail when requested
+func (m *Model) refreshLogView(follow bool) {
+	m.logViewport.SetContent(m.formatLogs())
+
+	if idx := m.search.currentEntry(); idx >= 0 && !follow {
+		line := m.logLine(idx)
+		if line < m.logViewport.YOffset || line >= m.logViewport.YOffset+m.logViewport.Height {
+			m.logViewport.SetYOffset(line - m.logViewport.Height/2)
+		}
+		return
+	}
+
+	if follow {
+		m.logViewport.GotoBottom()
+	}
+}
+
+// logLine returns the viewport line where a log entry starts
+func (m *Model) logLine(idx int) int {
+	line := 0
+	for i := 0; i < idx; i++ {
+		line += 1 + strings.Count(m.logs.At(i).Message, "\n")
+	}
+	return line
+}
+
+// updateSearchInput handles keys while the log search prompt is open
+func (m *Model) updateSearchInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		if err := m.search.confirm(); err != nil {
+			m.errMsg = err.Error()
+			return nil
+		}
+		m.errMsg = ""
+		m.search.index(m.logs)
+		// Start at the most recent match
+		m.search.focusLatest(m.logs)
+		m.refreshLogView(false)
+		return nil
+
+	case "esc":
+		m.search.typing = false
+		m.search.input.Blur()
+		return nil
+
+	case "ctrl+r":
+		m.search.toggleRegex()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.search.input, cmd = m.search.input.Update(msg)
+	return cmd
+}
+
+// updatePrompt handles keys while a prompt is open
+func (m *Model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		kind, value := m.prompt.kind, strings.TrimSpace(m.prompt.value())
+		m.prompt.close()
+		switch kind {
+		case promptSeekTime:
+			return m.submitSeekTime(value)
+		case promptExport:
+			return m.exportLogs(value)
+		case promptSnapshot:
+			return m.saveSnapshot(value)
+		case promptGraph:
+			return m.exportGraph(value)
+		case promptPort:
+			return m.lookupPort(value)
+		}
+		return nil
+
+	case "esc":
+		m.prompt.close()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.prompt.input, cmd = m.prompt.input.Update(msg)
+	return cmd
+}
+
+// logViewportKeyMap limits viewport scrolling to keys that don't clash
+// with service actions
+func logViewportKeyMap() viewport.KeyMap {
+	return viewport.KeyMap{
+		PageDown:     key.NewBinding(key.WithKeys("pgdown")),
+		PageUp:       key.NewBinding(key.WithKeys("pgup")),
+		HalfPageUp:   key.NewBinding(key.WithKeys("ctrl+u")),
+		HalfPageDown: key.NewBinding(key.WithKeys("ctrl+d")),
+		Up:           key.NewBinding(key.WithKeys("up", "k")),
+		Down:         key.NewBinding(key.WithKeys("down", "j")),
+	}
+}
+
 // formatProcessTree formats the process tree for display
 func (m *Model) formatProcessTree() string {
 	if len(m.processes) == 0 {
@@ -542,62 +1239,61 @@ func (m *Model) formatProcessTree() string {
 	var sb strings.Builder
 
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("170")).
+		Foreground(m.theme.Accent).
 		Bold(true)
 
 	sb.WriteString(headerStyle.Render(fmt.Sprintf("Process Tree for %s\n\n", m.currentService)))
 
 	for _, proc := range m.processes {
-		m.renderProcess(&sb, proc, "", true)
+		renderTree(&sb, procNode{proc: proc}, "", true, m.procNodeChildren, m.procNodeLine)
 	}
 
 	sb.WriteString("\n")
 	sb.WriteString(lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Render("Press 'l' to return to logs view"))
+		Foreground(m.theme.Muted).
+		Render("↑↓ select • space threads • enter logs of process • + with descendants • 'l' back to logs"))
 
 	return sb.String()
 }
 
-// renderProcess recursively renders a process and its children
-func (m *Model) renderProcess(sb *strings.Builder, proc *types.Process, prefix string, isLast bool) {
-	pidStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-	nameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-	cmdStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+// procNodeLine formats a row of the process tree
+func (m *Model) procNodeLine(node procNode, branch string) string {
+	if node.thread != nil {
+		return m.threadLine(node.thread, branch)
+	}
+	return m.processLine(node.proc, branch)
+}
 
-	// Tree characters
-	var connector string
-	if prefix == "" {
-		connector = "┌─"
-	} else if isLast {
-		connector = "└─"
-	} else {
-		connector = "├─"
+// processLine formats one process of the tree: ├─ [PID] name: cmdline
+func (m *Model) processLine(proc *types.Process, branch string) string {
+	pidStyle := lipgloss.NewStyle().Foreground(m.theme.Success)
+	nameStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
+	cmdStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	// Mark the highlighted process when the pane has focus
+	gutter := " "
+	if proc.PID == m.selectedPID && m.focus == focusLogs {
+		gutter = lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▌")
+	}
+
+	// Multithreaded processes can be expanded with space
+	threads := ""
+	if n := proc.NumThreads; n > 1 {
+		arrow := "▸"
+		if m.expandedPIDs[proc.PID] {
+			arrow = "▾"
+		}
+		threads = cmdStyle.Render(fmt.Sprintf(" %s %d threads", arrow, n))
 	}
 
-	// Format: ├─ [PID] name: cmdline
-	line := fmt.Sprintf("%s%s %s %s: %s\n",
-		prefix,
-		connector,
+	return fmt.Sprintf("%s%s %s %s: %s%s",
+		gutter,
+		branch,
 		pidStyle.Render(fmt.Sprintf("[%d]", proc.PID)),
 		nameStyle.Render(proc.Name),
 		cmdStyle.Render(truncate(proc.Cmdline, 60)),
+		threads,
 	)
-	sb.WriteString(line)
-
-	// Render children
-	childPrefix := prefix
-	if prefix == "" {
-		childPrefix = "  "
-	} else if isLast {
-		childPrefix = prefix + "   "
-	} else {
-		childPrefix = prefix + "│  "
-	}
-
-	for i, child := range proc.Children {
-		m.renderProcess(sb, child, childPrefix, i == len(proc.Children)-1)
-	}
 }
 
 // truncate truncates a string to a maximum length
@@ -611,7 +1307,7 @@ func truncate(s string, maxLen int) string {
 // renderNoProcessesState shows message when no processes found
 func (m *Model) renderNoProcessesState() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(m.theme.Muted).
 		Align(lipgloss.Center).
 		Width(m.logViewport.Width).
 		MarginTop(m.logViewport.Height / 3)
@@ -630,14 +1326,14 @@ func (m *Model) renderNoProcessesState() string {
 // renderEmptyState shows helpful message when no service selected
 func (m *Model) renderEmptyState() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("170")).
+		Foreground(m.theme.Accent).
 		Bold(true)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(m.theme.Muted)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("42")).
+		Foreground(m.theme.Success).
 		Bold(true)
 
 	// Build the content
@@ -648,7 +1344,8 @@ func (m *Model) renderEmptyState() string {
 	content.WriteString(labelStyle.Render("Navigation:\n"))
 	content.WriteString("  " + keyStyle.Render("↑↓") + labelStyle.Render(" or ") + keyStyle.Render("j/k") + labelStyle.Render(" - Move up/down\n"))
 	content.WriteString("  " + keyStyle.Render("Enter") + labelStyle.Render(" - View logs for selected service\n"))
-	content.WriteString("  " + keyStyle.Render("/") + labelStyle.Render(" - Filter/search services\n\n"))
+	content.WriteString("  " + keyStyle.Render("/") + labelStyle.Render(" - Filter/search services\n"))
+	content.WriteString("  " + keyStyle.Render("Tab") + labelStyle.Render(" - Switch focus between services and logs\n\n"))
 	content.WriteString(labelStyle.Render("Service Actions:\n"))
 	content.WriteString("  " + keyStyle.Render("r") + labelStyle.Render(" - Restart service\n"))
 	content.WriteString("  " + keyStyle.Render("s") + labelStyle.Render(" - Stop service\n"))
@@ -657,12 +1354,44 @@ func (m *Model) renderEmptyState() string {
 	content.WriteString("  " + keyStyle.Render("d") + labelStyle.Render(" - Disable from boot\n\n"))
 	content.WriteString(labelStyle.Render("View Modes:\n"))
 	content.WriteString("  " + keyStyle.Render("p") + labelStyle.Render(" - Show process tree (see what's running!)\n"))
-	content.WriteString("  " + keyStyle.Render("l") + labelStyle.Render(" - Return to logs view\n\n"))
+	content.WriteString("  " + keyStyle.Render("Enter/+") + labelStyle.Render(" - Logs of a process (+ descendants) in the tree\n"))
+	content.WriteString("  " + keyStyle.Render("l") + labelStyle.Render(" - Return to logs view\n"))
+	content.WriteString("  " + keyStyle.Render("Space") + labelStyle.Render(" - Mark service for merged logs\n"))
+	content.WriteString("  " + keyStyle.Render("M") + labelStyle.Render(" - Merged logs of marked services (1-9 mute)\n\n"))
+	content.WriteString(labelStyle.Render("Logs (when focused):\n"))
+	content.WriteString("  " + keyStyle.Render("/") + labelStyle.Render(" - Search logs (ctrl+r toggles regex)\n"))
+	content.WriteString("  " + keyStyle.Render("n/N") + labelStyle.Render(" - Next/previous match\n"))
+	content.WriteString("  " + keyStyle.Render("Esc") + labelStyle.Render(" - Clear search\n"))
+	content.WriteString("  " + keyStyle.Render("↑↓/jk") + labelStyle.Render(" - Select a log entry\n"))
+	content.WriteString("  " + keyStyle.Render("Enter") + labelStyle.Render(" - Show all journal fields of the entry\n"))
+	content.WriteString("  " + keyStyle.Render("y") + labelStyle.Render(" - Copy the raw entry (JSON)\n"))
+	content.WriteString("  " + keyStyle.Render("c") + labelStyle.Render(" - Toggle catalog explanations (journalctl -x)\n"))
+	content.WriteString("  " + keyStyle.Render("v/V") + labelStyle.Render(" - Show fewer/more log levels\n"))
+	content.WriteString("  " + keyStyle.Render("b") + labelStyle.Render(" - Cycle boots (all → current → previous …)\n"))
+	content.WriteString("  " + keyStyle.Render("T") + labelStyle.Render(" - Show logs up to a time\n"))
+	content.WriteString("  " + keyStyle.Render("i") + labelStyle.Render(" - Log rate, error rate and top messages\n"))
+	content.WriteString("  " + keyStyle.Render("x") + labelStyle.Render(" - Export displayed logs (.log, .json, .export)\n"))
+	content.WriteString("  " + keyStyle.Render("S") + labelStyle.Render(" - Save a snapshot archive of the service\n"))
+	content.WriteString("  " + keyStyle.Render("↑") + labelStyle.Render(" at the top - Load older entries\n\n"))
 	content.WriteString(labelStyle.Render("Filters:\n"))
-	content.WriteString("  " + keyStyle.Render("f") + labelStyle.Render(" - Cycle filter (all → running → failed)\n"))
+	content.WriteString("  " + keyStyle.Render("f") + labelStyle.Render(" - Cycle filter (all → running → failed → flapping)\n"))
 	content.WriteString("  " + keyStyle.Render("1") + labelStyle.Render(" - Show all services\n"))
 	content.WriteString("  " + keyStyle.Render("2") + labelStyle.Render(" - Show only running\n"))
-	content.WriteString("  " + keyStyle.Render("3") + labelStyle.Render(" - Show only failed\n\n"))
+	content.WriteString("  " + keyStyle.Render("3") + labelStyle.Render(" - Show only failed\n"))
+	content.WriteString("  " + keyStyle.Render("4") + labelStyle.Render(" - Show only flapping (crash loops)\n"))
+	content.WriteString("  " + keyStyle.Render("o") + labelStyle.Render(" - Sort by security exposure (most exposed first)\n"))
+	content.WriteString("  " + keyStyle.Render("F") + labelStyle.Render(" - Triage failed units (exit status, last errors)\n"))
+	content.WriteString("  " + keyStyle.Render("C") + labelStyle.Render(" - Browse coredumps (stack traces, jump to logs)\n"))
+	content.WriteString("  " + keyStyle.Render("D") + labelStyle.Render(" - Dependency tree of the service (R for reverse)\n"))
+	content.WriteString("  " + keyStyle.Render("G") + labelStyle.Render(" - Export the dependency graph (.dot, .mmd)\n"))
+	content.WriteString("  " + keyStyle.Render("A") + labelStyle.Render(" - Boot analysis (blame, critical chain, Gantt chart)\n"))
+	content.WriteString("  " + keyStyle.Render("X") + labelStyle.Render(" - Security review of the service (exposure score, fixes)\n"))
+	content.WriteString("  " + keyStyle.Render("L") + labelStyle.Render(" - Lint the unit file and drop-ins\n"))
+	content.WriteString("  " + keyStyle.Render("E") + labelStyle.Render(" - Commands and environment, configured vs. actual\n"))
+	content.WriteString("  " + keyStyle.Render("O") + labelStyle.Render(" - Sockets and listening ports of the service\n"))
+	content.WriteString("  " + keyStyle.Render("W") + labelStyle.Render(" - Who listens on a port\n"))
+	content.WriteString("  " + keyStyle.Render("R") + labelStyle.Render(" - Change resource limits of the running service\n"))
+	content.WriteString("  " + keyStyle.Render("!") + labelStyle.Render(" - Run a command as a transient service or scope\n\n"))
 	content.WriteString(labelStyle.Render("Other:\n"))
 	content.WriteString("  " + keyStyle.Render("q") + labelStyle.Render(" - Quit application\n"))
 
@@ -677,13 +1406,18 @@ func (m *Model) renderEmptyState() string {
 // renderNoLogsState shows message when service has no logs
 func (m *Model) renderNoLogsState() string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(m.theme.Muted).
 		Align(lipgloss.Center).
 		Width(m.logViewport.Width).
 		MarginTop(m.logViewport.Height / 3)
 
+	source := fmt.Sprintf("Service: %s", m.currentService)
+	if m.merged {
+		source = fmt.Sprintf("Units: %s", strings.Join(m.activeLogUnits(), ", "))
+	}
+
 	content := "NO LOGS FOUND\n\n" +
-		fmt.Sprintf("Service: %s\n\n", m.currentService) +
+		source + "\n\n" +
 		"The service may not have generated\n" +
 		"any logs recently, or you may need\n" +
 		"additional permissions.\n\n" +
@@ -701,11 +1435,18 @@ func (m *Model) View() string {
 	// Styles
 	borderStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(m.theme.Border)
+
+	// Highlight the border of the focused pane
+	focusedBorder := borderStyle.BorderForeground(m.theme.Accent)
+	leftBorder, rightBorder := focusedBorder, borderStyle
+	if m.focus == focusLogs {
+		leftBorder, rightBorder = borderStyle, focusedBorder
+	}
 
 	// Left pane: service list
 	leftWidth := m.width * 30 / 100
-	leftPane := borderStyle.
+	leftPane := leftBorder.
 		Width(leftWidth).
 		Height(m.height - 4).
 		Render(m.serviceList.View())
@@ -714,46 +1455,71 @@ func (m *Model) View() string {
 	rightWidth := m.width - leftWidth - 2
 
 	var logTitle string
-	if m.currentService != "" {
+	if m.merged {
+		// Show the merged sources and their mute toggles
+		modeAndActions := m.renderPriorityFilter() + m.renderLogRange()
+		if m.search.active() {
+			modeAndActions += lipgloss.NewStyle().
+				Foreground(m.theme.Warning).
+				Render(" " + m.search.status() + " [n]ext [N]prev")
+		}
+
+		logTitle = lipgloss.NewStyle().
+			Background(m.theme.Background).
+			Width(rightWidth).
+			Padding(0, 1).
+			Render(fmt.Sprintf("MERGED LOGS: %s %s", m.renderMergedSources(), modeAndActions))
+	} else if m.currentService != "" {
 		// Show service name and actions
 		serviceName := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("170")).
+			Foreground(m.theme.Accent).
 			Render(m.currentService)
 
 		var modeAndActions string
 		if m.showProcessTree {
 			modeAndActions = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("42")).
+				Foreground(m.theme.Success).
 				Render(" 🌳 PROCESS TREE [l]ogs")
+		} else if m.search.active() {
+			modeAndActions = m.renderPriorityFilter() + m.renderLogRange() + lipgloss.NewStyle().
+				Foreground(m.theme.Warning).
+				Render(" "+m.search.status()+" [n]ext [N]prev")
 		} else {
-			modeAndActions = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("240")).
+			modeAndActions = m.renderPriorityFilter() + m.renderLogRange() + lipgloss.NewStyle().
+				Foreground(m.theme.Muted).
 				Render(" [r]estart [s]top [t]art [p]rocesses")
 		}
 
 		logTitle = lipgloss.NewStyle().
-			Background(lipgloss.Color("235")).
+			Background(m.theme.Background).
 			Width(rightWidth).
 			Padding(0, 1).
 			Render(fmt.Sprintf("LOGS: %s %s", serviceName, modeAndActions))
 	} else {
 		logTitle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("240")).
-			Background(lipgloss.Color("235")).
+			Foreground(m.theme.Muted).
+			Background(m.theme.Background).
 			Width(rightWidth).
 			Padding(0, 1).
 			Render("LOGS")
 	}
 
-	rightPane := borderStyle.
+	rightPane := rightBorder.
 		Width(rightWidth).
 		Height(m.height - 4).
-		Render(lipgloss.JoinVertical(lipgloss.Left, logTitle, m.logViewport.View()))
+		Render(lipgloss.JoinVertical(lipgloss.Left, logTitle, m.renderLogPane()))
 
 	// Combine panes
 	content := lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	if m.triage != nil {
+		content = m.renderTriage()
+	} else if m.coredumps != nil {
+		content = m.renderCoredumps()
+	} else if m.deps != nil {
+		content = m.renderDeps()
+	}
 
 	// Status bar
 	statusBar := m.renderStatusBar()
@@ -761,19 +1527,98 @@ func (m *Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, content, statusBar)
 }
 
+// renderPriorityFilter shows the active log priority threshold, if any
+func (m *Model) renderPriorityFilter() string {
+	if m.maxPriority >= types.PriorityDebug {
+		return ""
+	}
+	style, _ := m.theme.priorityStyle(m.maxPriority)
+	return " " + style.Render(fmt.Sprintf("[%s+]", m.maxPriority))
+}
+
+// renderLogPane renders the logs viewport with its side panels, or an
+// overlay (entry fields, statistics, boot analysis, security, lint, resource
+// limits, run form, environment, sockets) in its place
+func (m *Model) renderLogPane() string {
+	if m.detail != nil {
+		return m.renderLogDetail()
+	}
+	if m.statsOpen {
+		return m.renderStats()
+	}
+	if m.analysis != nil {
+		return m.renderAnalysis()
+	}
+	if m.security != nil {
+		return m.renderSecurity()
+	}
+	if m.lint != nil {
+		return m.renderLint()
+	}
+	if m.limits != nil {
+		return m.renderLimits()
+	}
+	if m.run != nil {
+		return m.renderRun()
+	}
+	if m.environment != nil {
+		return m.renderEnvironment()
+	}
+	if m.sockets != nil {
+		return m.renderSockets()
+	}
+
+	// Flapping services get their restart timeline above the logs
+	logs := m.logViewport
+	timeline := ""
+	if m.currentService != "" && m.showingLogs() && m.flaps.Flapping(m.currentService) {
+		timeline = m.renderFlapTimeline(m.currentService, logs.Width)
+		logs.Height--
+	}
+
+	pane := logs.View()
+	if m.catalogVisible() {
+		// Narrow a copy of the viewport to make room for the panel
+		width := m.catalogWidth()
+		narrow := logs
+		narrow.Width -= width
+		pane = lipgloss.JoinHorizontal(lipgloss.Top,
+			narrow.View(), m.renderCatalog(width, logs.Height))
+	}
+
+	if timeline != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, timeline, pane)
+	}
+	return pane
+}
+
 // renderStatusBar renders the bottom status bar
 func (m *Model) renderStatusBar() string {
+	// Prompts replace the status bar while typing
+	if m.prompt.active() {
+		hint := lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Render("  " + m.prompt.hint)
+		return m.prompt.input.View() + hint
+	}
+	if m.search.typing {
+		hint := lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Render("  enter search • esc cancel • ctrl+r toggle regex")
+		return m.search.input.View() + hint
+	}
+
 	// If there's a status or error message, show it prominently
 	if m.statusMsg != "" {
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("42")).
+			Foreground(m.theme.Success).
 			Bold(true).
 			Render(fmt.Sprintf("✓ %s", m.statusMsg))
 	}
 
 	if m.errMsg != "" {
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(m.theme.Error).
 			Bold(true).
 			Render(fmt.Sprintf("✗ Error: %s", m.errMsg))
 	}
@@ -783,69 +1628,87 @@ func (m *Model) renderStatusBar() string {
 
 	// Navigation always available
 	helpParts = append(helpParts,
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Navigate: "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("↑↓/jk"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("Navigate: "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("↑↓/jk"),
 	)
 
 	// Selection
 	helpParts = append(helpParts,
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" • Select: "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("enter"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Select: "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("enter"),
 	)
 
+	// Pane focus and log search
+	if len(m.logUnits) > 0 {
+		helpParts = append(helpParts,
+			lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Focus: "),
+			lipgloss.NewStyle().Foreground(m.theme.Text).Render("tab"),
+		)
+		if m.focus == focusLogs && !m.showProcessTree {
+			helpParts = append(helpParts,
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Search: "),
+				lipgloss.NewStyle().Foreground(m.theme.Text).Render("/"),
+			)
+		}
+	}
+
 	// Actions (only if service selected)
 	if m.currentService != "" {
 		if m.showProcessTree {
 			helpParts = append(helpParts,
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" • View: "),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("l"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("ogs"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • View: "),
+				lipgloss.NewStyle().Foreground(m.theme.Success).Render("l"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render("ogs"),
 			)
 		} else {
 			helpParts = append(helpParts,
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" • Actions: "),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("r"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("estart "),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("s"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("top "),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("t"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("start "),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("p"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("rocesses"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Actions: "),
+				lipgloss.NewStyle().Foreground(m.theme.Success).Render("r"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render("estart "),
+				lipgloss.NewStyle().Foreground(m.theme.Warning).Render("s"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render("top "),
+				lipgloss.NewStyle().Foreground(m.theme.Success).Render("t"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render("start "),
+				lipgloss.NewStyle().Foreground(m.theme.Success).Render("p"),
+				lipgloss.NewStyle().Foreground(m.theme.Muted).Render("rocesses"),
 			)
 		}
 	}
 
 	// Filter
 	helpParts = append(helpParts,
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" • Filter: "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("f"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("/"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("1"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("all "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("2"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("run "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("3"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("fail"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Filter: "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("f"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("/"),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("1"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("all "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("2"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("run "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("3"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("fail "),
+		lipgloss.NewStyle().Foreground(m.theme.Text).Render("4"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render("flap"),
 	)
 
 	// Quit
 	helpParts = append(helpParts,
-		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" • Quit: "),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("q"),
+		lipgloss.NewStyle().Foreground(m.theme.Muted).Render(" • Quit: "),
+		lipgloss.NewStyle().Foreground(m.theme.Error).Render("q"),
 	)
 
 	// Service count with filter indicator
 	filterIndicator := ""
 	switch m.filterMode {
 	case "running":
-		filterIndicator = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(" [RUNNING]")
+		filterIndicator = lipgloss.NewStyle().Foreground(m.theme.Success).Render(" [RUNNING]")
 	case "failed":
-		filterIndicator = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(" [FAILED]")
+		filterIndicator = lipgloss.NewStyle().Foreground(m.theme.Error).Render(" [FAILED]")
+	case "flapping":
+		filterIndicator = lipgloss.NewStyle().Foreground(m.theme.Error).Render(" [FLAPPING]")
 	}
 
 	serviceCount := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
+		Foreground(m.theme.Muted).
 		Render(fmt.Sprintf(" │ Services: %d%s", len(m.services), filterIndicator))
 
 	helpParts = append(helpParts, serviceCount)
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// threadsRefresh is how often the process tree is reloaded while threads
+// are expanded, to update their CPU usage
+const threadsRefresh = 2 * time.Second
+
+// procNode is a row of the process tree: a process, or one of its threads
+// when the process is expanded
+type procNode struct {
+	proc   *types.Process
+	thread *types.Thread
+}
+
+// flattenProcesses lists processes in the order the tree renders them
+func flattenProcesses(procs []*types.Process) []*types.Process {
+	var flat []*types.Process
+	for _, proc := range procs {
+		flat = append(flat, proc)
+		flat = append(flat, flattenProcesses(proc.Children)...)
+	}
+	return flat
+}
+
+// procNodeChildren returns the rows under a process: its threads when
+// expanded, then its child processes
+func (m *Model) procNodeChildren(node procNode) []procNode {
+	if node.thread != nil {
+		return nil
+	}
+
+	var kids []procNode
+	if m.expandedPIDs[node.proc.PID] {
+		for i := range node.proc.Threads {
+			kids = append(kids, procNode{proc: node.proc, thread: &node.proc.Threads[i]})
+		}
+	}
+	for _, child := range node.proc.Children {
+		kids = append(kids, procNode{proc: child})
+	}
+	return kids
+}
+
+// processRows lists the rows of the process tree in render order
+func (m *Model) processRows() []procNode {
+	var rows []procNode
+	var walk func(node procNode)
+	walk = func(node procNode) {
+		rows = append(rows, node)
+		for _, kid := range m.procNodeChildren(node) {
+			walk(kid)
+		}
+	}
+	for _, proc := range m.processes {
+		walk(procNode{proc: proc})
+	}
+	return rows
+}
+
+// toggleThreads expands or collapses the threads of the highlighted
+// process, reloading the tree to read them when expanding
+func (m *Model) toggleThreads() tea.Cmd {
+	if findProcess(m.processes, m.selectedPID) == nil {
+		return nil
+	}
+	if m.expandedPIDs[m.selectedPID] {
+		delete(m.expandedPIDs, m.selectedPID)
+		m.logViewport.SetContent(m.formatProcessTree())
+		return nil
+	}
+	m.expandedPIDs[m.selectedPID] = true
+	m.processesLoadedAt = time.Now()
+	return m.reloadProcessTree(true)
+}
+
+// refreshThreads reloads the process tree periodically while threads are
+// expanded
+func (m *Model) refreshThreads() tea.Cmd {
+	if !m.showProcessTree || len(m.expandedPIDs) == 0 || time.Since(m.processesLoadedAt) < threadsRefresh {
+		return nil
+	}
+	m.processesLoadedAt = time.Now()
+	return m.reloadProcessTree(true)
+}
+
+// threadLine formats one thread under its process: ├─ ↳ TID name state
+// CPU time and usage
+func (m *Model) threadLine(thread *types.Thread, branch string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	usage := mutedStyle.Render("   …")
+	if thread.CPUPercent >= 0 {
+		color := m.theme.Muted
+		switch {
+		case thread.CPUPercent >= 50:
+			color = m.theme.Error
+		case thread.CPUPercent >= 10:
+			color = m.theme.Warning
+		}
+		usage = lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%3.0f%%", thread.CPUPercent))
+	}
+
+	return fmt.Sprintf(" %s %s %s %s %s %s",
+		branch,
+		mutedStyle.Render("↳"),
+		usage,
+		mutedStyle.Render(fmt.Sprintf("%-7d", thread.TID)),
+		fmt.Sprintf("%-16s", thread.Name),
+		mutedStyle.Render(fmt.Sprintf("%s %s", thread.State, thread.CPUTime.Round(10*time.Millisecond))),
+	)
+}
+
+// findProcess returns the process with the given PID in a tree, or nil
+func findProcess(procs []*types.Process, pid int) *types.Process {
+	for _, proc := range flattenProcesses(procs) {
+		if proc.PID == pid {
+			return proc
+		}
+	}
+	return nil
+}
+
+// keepProcessSelection keeps the highlighted process across reloads,
+// falling back to the first process when it has exited
+func (m *Model) keepProcessSelection() {
+	if findProcess(m.processes, m.selectedPID) != nil {
+		return
+	}
+	m.selectedPID = 0
+	if len(m.processes) > 0 {
+		m.selectedPID = m.processes[0].PID
+	}
+}
+
+// moveProcessSelection moves the process highlight up or down
+func (m *Model) moveProcessSelection(delta int) {
+	flat := flattenProcesses(m.processes)
+	if len(flat) == 0 {
+		return
+	}
+
+	idx := 0
+	for i, proc := range flat {
+		if proc.PID == m.selectedPID {
+			idx = i
+			break
+		}
+	}
+	idx = max(0, min(len(flat)-1, idx+delta))
+	m.selectedPID = flat[idx].PID
+	m.logViewport.SetContent(m.formatProcessTree())
+
+	// The tree starts after the header and a blank line, and expanded
+	// threads take a line each
+	line := 2
+	for i, row := range m.processRows() {
+		if row.thread == nil && row.proc.PID == m.selectedPID {
+			line += i
+			break
+		}
+	}
+	if line < m.logViewport.YOffset {
+		m.logViewport.SetYOffset(line)
+	} else if bottom := m.logViewport.YOffset + m.logViewport.Height; line >= bottom {
+		m.logViewport.SetYOffset(line - m.logViewport.Height + 1)
+	}
+}
+
+// filterLogsToProcess switches to the logs of the highlighted process,
+// optionally including its descendants (e.g. one gunicorn or postgres
+// worker and the processes it forked)
+func (m *Model) filterLogsToProcess(descendants bool) tea.Cmd {
+	proc := findProcess(m.processes, m.selectedPID)
+	if proc == nil {
+		return nil
+	}
+
+	pids := []int{proc.PID}
+	if descendants {
+		for _, child := range flattenProcesses(proc.Children) {
+			pids = append(pids, child.PID)
+		}
+	}
+
+	m.logPIDs = pids
+	m.showProcessTree = false
+	m.resetLogs()
+	return m.refreshLogs()
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies what a prompt's answer is used for
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptSeekTime
+	promptExport
+	promptSnapshot
+	promptGraph
+	promptPort
+)
+
+// prompt is a single-line input shown in place of the status bar
+type prompt struct {
+	input textinput.Model
+	kind  promptKind
+	hint  string
+}
+
+// newPrompt creates an inactive prompt
+func newPrompt() prompt {
+	input := textinput.New()
+	input.CharLimit = 256
+	return prompt{input: input}
+}
+
+// active reports whether the prompt is open
+func (p *prompt) active() bool {
+	return p.kind != promptNone
+}
+
+// open shows the prompt with a label, initial value and key hint
+func (p *prompt) open(kind promptKind, label, value, hint string) tea.Cmd {
+	p.kind = kind
+	p.hint = hint
+	p.input.Prompt = label
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	return p.input.Focus()
+}
+
+// close hides the prompt
+func (p *prompt) close() {
+	p.kind = promptNone
+	p.input.Blur()
+}
+
+// value returns the text entered so far
+func (p *prompt) value() string {
+	return p.input.Value()
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sdtop/internal/systemd"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fields of the run form, in order
+const (
+	runCommand = iota
+	runName
+	runDirectory
+	runEnvironment
+	runUser
+	runLimits
+)
+
+// runFields label the run form's inputs, with a hint shown under the
+// focused one
+var runFields = []struct{ label, hint string }{
+	{"Command", "program and arguments, quoted like in a shell"},
+	{"Unit name", "empty for run-sdtop-<random>; the .service or .scope suffix is added"},
+	{"Directory", "working directory, empty for /"},
+	{"Environment", "KEY=VALUE assignments separated by spaces"},
+	{"User", "empty to run as root, or as yourself for a scope"},
+	{"Limits", "e.g. MemoryMax=512M CPUQuota=50% TasksMax=64"},
+}
+
+// runView is the form for launching a command as a transient unit
+type runView struct {
+	inputs   []textinput.Model
+	focus    int
+	scope    bool   // Run as a scope instead of a service
+	errField int    // Input the error is about
+	err      string // Validation or start error
+	starting bool   // Waiting for systemd to start the unit
+}
+
+// runStartedMsg is sent when a transient unit has started or failed to
+type runStartedMsg struct {
+	unit string
+	err  error
+}
+
+// openRun shows the form for running a command as a transient unit
+func (m *Model) openRun() tea.Cmd {
+	v := &runView{inputs: make([]textinput.Model, len(runFields))}
+	for i := range v.inputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 1024
+		input.Width = max(20, m.logViewport.Width-20)
+		v.inputs[i] = input
+	}
+	m.run = v
+	return v.inputs[0].Focus()
+}
+
+// updateRun handles keys in the run form
+func (m *Model) updateRun(msg tea.KeyMsg) tea.Cmd {
+	v := m.run
+	if v.starting {
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.run = nil
+		return nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "shift+tab":
+		return v.focusInput(v.focus - 1)
+	case "down", "tab":
+		return v.focusInput(v.focus + 1)
+	case "ctrl+t":
+		v.scope = !v.scope
+		return nil
+	case "enter":
+		return m.startRun()
+	}
+
+	var cmd tea.Cmd
+	v.inputs[v.focus], cmd = v.inputs[v.focus].Update(msg)
+	return cmd
+}
+
+// focusInput moves the cursor to another input, wrapping around
+func (v *runView) focusInput(i int) tea.Cmd {
+	v.inputs[v.focus].Blur()
+	v.focus = (i + len(v.inputs)) % len(v.inputs)
+	return v.inputs[v.focus].Focus()
+}
+
+// options parses the form, setting the error on the first invalid input
+func (v *runView) options() (systemd.TransientOptions, bool) {
+	fail := func(field int, err error) (systemd.TransientOptions, bool) {
+		v.errField, v.err = field, err.Error()
+		return systemd.TransientOptions{}, false
+	}
+	value := func(field int) string { return strings.TrimSpace(v.inputs[field].Value()) }
+
+	opts := systemd.TransientOptions{
+		WorkingDirectory: value(runDirectory),
+		User:             value(runUser),
+		Scope:            v.scope,
+	}
+
+	var err error
+	if opts.Command, err = systemd.SplitCommand(value(runCommand)); err != nil {
+		return fail(runCommand, err)
+	}
+	if len(opts.Command) == 0 {
+		return fail(runCommand, fmt.Errorf("enter a command to run"))
+	}
+	if opts.Name, err = systemd.TransientUnitName(value(runName), v.scope); err != nil {
+		return fail(runName, err)
+	}
+	if opts.Environment, err = systemd.SplitCommand(value(runEnvironment)); err != nil {
+		return fail(runEnvironment, err)
+	}
+	for _, env := range opts.Environment {
+		if key, _, ok := strings.Cut(env, "="); !ok || key == "" {
+			return fail(runEnvironment, fmt.Errorf("%q is not a KEY=VALUE assignment", env))
+		}
+	}
+	limits, err := systemd.SplitCommand(value(runLimits))
+	if err == nil {
+		opts.Limits, err = systemd.ParseLimitAssignments(limits)
+	}
+	if err != nil {
+		return fail(runLimits, err)
+	}
+
+	v.err = ""
+	return opts, true
+}
+
+// startRun validates the form and starts the transient unit
+func (m *Model) startRun() tea.Cmd {
+	opts, ok := m.run.options()
+	if !ok {
+		return nil
+	}
+	m.run.starting = true
+
+	return func() tea.Msg {
+		unit, err := m.manager.StartTransient(opts)
+		return runStartedMsg{unit: unit, err: err}
+	}
+}
+
+// handleRunStarted selects a started unit and tails its logs, or shows
+// why it didn't start
+func (m *Model) handleRunStarted(msg runStartedMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.run != nil {
+			m.run.starting = false
+			m.run.errField, m.run.err = -1, msg.err.Error()
+		}
+		return nil
+	}
+
+	m.run = nil
+	// Tail the new unit from its start, going back to the usual range on
+	// the next selection
+	m.restoreRange()
+	m.jumpToRange(time.Time{}, time.Time{})
+	m.focus = focusLogs
+	m.highlightService(msg.unit)
+	return tea.Batch(
+		m.loadServices,
+		m.openServiceLogs(msg.unit),
+		func() tea.Msg { return statusMsgType("Started " + msg.unit) },
+	)
+}
+
+// renderRun draws the run form in place of the logs
+func (m *Model) renderRun() string {
+	v := m.run
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	errStyle := lipgloss.NewStyle().Foreground(m.theme.Error)
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("RUN COMMAND") +
+		labelStyle.Render("  ↑↓ field • enter start • ctrl+t service/scope • esc close") + "\n\n")
+
+	kind := "service, started and supervised by systemd"
+	if v.scope {
+		kind = "scope around a process started by sdtop"
+	}
+	sb.WriteString(labelStyle.Render("Run as: ") + kind + "\n\n")
+
+	for i, field := range runFields {
+		marker := "  "
+		if i == v.focus {
+			marker = titleStyle.Render("▶ ")
+		}
+		sb.WriteString(marker + fmt.Sprintf("%-12s", field.label) + v.inputs[i].View() + "\n")
+
+		switch {
+		case v.err != "" && v.errField == i:
+			sb.WriteString("    " + errStyle.Render("✗ "+v.err) + "\n")
+		case i == v.focus:
+			hint := field.hint
+			if i == runDirectory && v.scope {
+				// sdtop starts a scope's process itself
+				hint = "working directory, empty for sdtop's own"
+			}
+			sb.WriteString("    " + labelStyle.Render(truncate(hint, max(10, width-4))) + "\n")
+		}
+	}
+
+	switch {
+	case v.starting:
+		sb.WriteString("\n" + labelStyle.Render("Starting..."))
+	case v.err != "" && v.errField < 0:
+		sb.WriteString("\n" + errStyle.Render("✗ "+v.err))
+	}
+
+	return m.statsFrame(sb.String())
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// securityView shows the sandboxing review of one service
+type securityView struct {
+	unit   string
+	report *types.SecurityReport // nil while loading
+	offset int                   // First rendered check
+}
+
+// securityLoadedMsg carries a service's security review
+type securityLoadedMsg struct {
+	report *types.SecurityReport
+	err    error
+}
+
+// exposureLoadedMsg carries the exposure scores of all services
+type exposureLoadedMsg struct {
+	scores map[string]float64
+}
+
+// openSecurity shows the security review of the current service in
+// place of the logs
+func (m *Model) openSecurity() tea.Cmd {
+	unit := m.currentService
+	m.security = &securityView{unit: unit}
+
+	return func() tea.Msg {
+		report, err := m.manager.AnalyzeSecurity(unit)
+		return securityLoadedMsg{report: report, err: err}
+	}
+}
+
+// handleSecurityLoaded shows a loaded review and keeps its score for the
+// service list
+func (m *Model) handleSecurityLoaded(msg securityLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.security = nil
+		m.errMsg = fmt.Sprintf("Failed to analyze security: %v", msg.err)
+		return nil
+	}
+	if m.security != nil && m.security.unit == msg.report.Unit {
+		m.security.report = msg.report
+		m.scrollSecurity()
+	}
+	if m.exposure == nil {
+		return nil
+	}
+	m.exposure[msg.report.Unit] = msg.report.Score
+	return m.applyFilter()
+}
+
+// toggleExposureSort sorts the service list by exposure, most exposed
+// first, scoring all services the first time
+func (m *Model) toggleExposureSort() tea.Cmd {
+	m.sortByExposure = !m.sortByExposure
+	if !m.sortByExposure || m.exposure != nil {
+		return m.applyFilter()
+	}
+	if m.scoringExposure {
+		return nil
+	}
+	m.scoringExposure = true
+
+	names := make([]string, len(m.allServices))
+	for i, svc := range m.allServices {
+		names[i] = svc.Name
+	}
+	return tea.Batch(
+		func() tea.Msg { return statusMsgType(fmt.Sprintf("Scoring %d services...", len(names))) },
+		func() tea.Msg {
+			scores := make(map[string]float64, len(names))
+			for _, name := range names {
+				if report, err := m.manager.AnalyzeSecurity(name); err == nil {
+					scores[name] = report.Score
+				}
+			}
+			return exposureLoadedMsg{scores: scores}
+		},
+	)
+}
+
+// sortByExposureScore orders services from most to least exposed, with
+// unscored services last
+func (m *Model) sortByExposureScore(services []types.Service) []types.Service {
+	sorted := append([]types.Service(nil), services...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, oki := m.exposure[sorted[i].Name]
+		sj, okj := m.exposure[sorted[j].Name]
+		if oki != okj {
+			return oki
+		}
+		return si > sj
+	})
+	return sorted
+}
+
+// exposureColor colors a score by its level
+func (t *Theme) exposureColor(score float64) lipgloss.TerminalColor {
+	switch {
+	case score >= 7.5:
+		return t.Error
+	case score >= 5:
+		return t.Warning
+	default:
+		return t.Success
+	}
+}
+
+// updateSecurity handles keys while the security review is open
+func (m *Model) updateSecurity(msg tea.KeyMsg) tea.Cmd {
+	v := m.security
+
+	switch msg.String() {
+	case "esc", "X", "q":
+		m.security = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.offset = max(0, v.offset-1)
+	case "down", "j":
+		v.offset++
+	}
+	m.scrollSecurity()
+	return nil
+}
+
+// securityRows is how many checks fit below the score
+func (m *Model) securityRows() int {
+	// The header, summary and a blank line take three lines
+	return max(1, m.logViewport.Height-2-3)
+}
+
+// scrollSecurity keeps the review scrolled within its checks
+func (m *Model) scrollSecurity() {
+	v := m.security
+	if v == nil || v.report == nil {
+		return
+	}
+	v.offset = max(0, min(v.offset, len(m.securityLines(v.report))-m.securityRows()))
+}
+
+// securityLines lists failing checks with their fix, then passing ones
+func (m *Model) securityLines(report *types.SecurityReport) []string {
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var lines []string
+	for _, check := range report.Checks {
+		if check.Exposure == 0 {
+			continue
+		}
+		color := m.theme.Warning
+		if check.Exposure >= 1 {
+			color = m.theme.Error
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(color).Render("✗ "+check.Name)+
+			labelStyle.Render(" - "+check.Description))
+		lines = append(lines, labelStyle.Render("    → ")+check.Fix)
+	}
+	for _, check := range report.Checks {
+		if check.Exposure == 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Success).Render("✓ "+check.Name)+
+				labelStyle.Render(" - "+check.Description))
+		}
+	}
+	return lines
+}
+
+// renderSecurity draws the security review in place of the logs: the
+// score, then failing checks with the directive that fixes them
+func (m *Model) renderSecurity() string {
+	v := m.security
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	header := titleStyle.Render("SECURITY · "+v.unit) + labelStyle.Render("  ↑↓ scroll • esc close")
+	if v.report == nil {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Reading unit properties..."))
+	}
+	report := v.report
+
+	score := lipgloss.NewStyle().Bold(true).Foreground(m.theme.exposureColor(report.Score)).
+		Render(fmt.Sprintf("%.1f %s", report.Score, systemd.ExposureLevel(report.Score)))
+	summary := labelStyle.Render("Overall exposure: ") + score + labelStyle.Render(" (0 hardened … 10 exposed)")
+
+	lines := m.securityLines(report)
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+	end := min(len(lines), v.offset+m.securityRows())
+
+	return m.statsFrame(header + "\n" + summary + "\n\n" + strings.Join(lines[min(v.offset, end):end], "\n"))
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// socketView shows the sockets of a service, or who listens on a port
+type socketView struct {
+	unit     string // Service whose sockets are shown, empty for a port lookup
+	port     int    // Port looked up
+	report   *types.SocketReport
+	showUnix bool // Show connected unix sockets too
+	offset   int  // First rendered line
+}
+
+// socketsLoadedMsg carries the sockets of a service or a port lookup
+type socketsLoadedMsg struct {
+	unit   string
+	port   int
+	report *types.SocketReport
+	err    error
+}
+
+// openSockets shows the sockets of the current service
+func (m *Model) openSockets() tea.Cmd {
+	m.sockets = &socketView{unit: m.currentService}
+	return m.loadSockets()
+}
+
+// promptPort asks which port to find the listener of
+func (m *Model) promptPort() tea.Cmd {
+	return m.prompt.open(promptPort, "who listens on port: ", "", "tcp and udp, any unit")
+}
+
+// lookupPort shows the processes listening on a port
+func (m *Model) lookupPort(value string) tea.Cmd {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return func() tea.Msg { return statusMsgType(fmt.Sprintf("%q is not a port number", value)) }
+	}
+	m.sockets = &socketView{port: port}
+	return m.loadSockets()
+}
+
+// loadSockets reads the sockets the view shows
+func (m *Model) loadSockets() tea.Cmd {
+	unit, port := m.sockets.unit, m.sockets.port
+	return func() tea.Msg {
+		var report *types.SocketReport
+		var err error
+		if unit != "" {
+			report, err = m.processManager.GetServiceSockets(unit)
+		} else {
+			report, err = m.processManager.ListeningOn(port)
+		}
+		return socketsLoadedMsg{unit: unit, port: port, report: report, err: err}
+	}
+}
+
+// handleSocketsLoaded shows loaded sockets
+func (m *Model) handleSocketsLoaded(msg socketsLoadedMsg) {
+	v := m.sockets
+	if v == nil || v.unit != msg.unit || v.port != msg.port {
+		return
+	}
+	if msg.err != nil {
+		m.sockets = nil
+		m.errMsg = fmt.Sprintf("Failed to read sockets: %v", msg.err)
+		return
+	}
+	v.report = msg.report
+	m.scrollSockets()
+}
+
+// updateSockets handles keys while the sockets view is open
+func (m *Model) updateSockets(msg tea.KeyMsg) tea.Cmd {
+	v := m.sockets
+
+	switch msg.String() {
+	case "esc", "O", "q":
+		m.sockets = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		v.offset = max(0, v.offset-1)
+	case "down", "j":
+		v.offset++
+	case "pgup":
+		v.offset = max(0, v.offset-m.logViewport.Height/2)
+	case "pgdown":
+		v.offset += m.logViewport.Height / 2
+	case "u":
+		v.showUnix = !v.showUnix
+	case "R":
+		return m.loadSockets()
+	}
+	m.scrollSockets()
+	return nil
+}
+
+// socketRows is how many lines fit below the sockets view's header
+func (m *Model) socketRows() int {
+	// The header takes one line
+	return max(1, m.logViewport.Height-2-1)
+}
+
+// scrollSockets keeps the view scrolled within its lines
+func (m *Model) scrollSockets() {
+	v := m.sockets
+	if v == nil || v.report == nil {
+		return
+	}
+	v.offset = max(0, min(v.offset, len(m.socketViewLines())-m.socketRows()))
+}
+
+// socketViewLines lists the service's sockets or the port's listeners,
+// noting processes that couldn't be inspected
+func (m *Model) socketViewLines() []string {
+	v := m.sockets
+	var lines []string
+	if v.unit != "" {
+		lines = m.serviceSocketLines()
+	} else {
+		lines = m.portLines()
+	}
+	if n := v.report.Unreadable; n > 0 {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(m.theme.Warning).
+			Render(fmt.Sprintf("%d processes couldn't be inspected; run as root to see all sockets", n)))
+	}
+	return lines
+}
+
+// renderSockets draws the sockets view in place of the logs
+func (m *Model) renderSockets() string {
+	v := m.sockets
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var header string
+	if v.unit != "" {
+		header = titleStyle.Render("SOCKETS · "+v.unit) +
+			labelStyle.Render("  ↑↓ scroll • u unix sockets • R refresh • esc close")
+	} else {
+		header = titleStyle.Render(fmt.Sprintf("PORT %d", v.port)) +
+			labelStyle.Render("  R refresh • esc close")
+	}
+	if v.report == nil {
+		return m.statsFrame(header + "\n\n" + labelStyle.Render("Reading /proc..."))
+	}
+
+	lines := m.socketViewLines()
+	for i := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(width).Render(lines[i])
+	}
+	end := min(len(lines), v.offset+m.socketRows())
+
+	return m.statsFrame(header + "\n" + strings.Join(lines[min(v.offset, end):end], "\n"))
+}
+
+// serviceSocketLines summarizes a service's listening ports and
+// connections, then lists the sockets of each process
+func (m *Model) serviceSocketLines() []string {
+	v := m.sockets
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+
+	var ports []string
+	seen := map[string]bool{}
+	connections, unix := 0, 0
+	byPID := map[int][]types.Socket{}
+	var pids []int
+
+	for _, s := range v.report.Sockets {
+		switch {
+		case s.Protocol == "unix":
+			unix++
+		case s.Listening:
+			port := fmt.Sprintf("%s %d", s.Protocol, s.LocalPort)
+			if !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		default:
+			connections++
+		}
+
+		if s.Protocol == "unix" && !s.Listening && !v.showUnix {
+			continue
+		}
+		if _, ok := byPID[s.PID]; !ok {
+			pids = append(pids, s.PID)
+		}
+		byPID[s.PID] = append(byPID[s.PID], s)
+	}
+	sort.Ints(pids)
+
+	listening := "nothing"
+	if len(ports) > 0 {
+		listening = strings.Join(ports, ", ")
+	}
+	lines := []string{
+		labelStyle.Render("Listening on ") + listening +
+			labelStyle.Render(fmt.Sprintf(" · %d connections · %d unix sockets", connections, unix)),
+	}
+
+	if len(pids) == 0 {
+		return append(lines, "", labelStyle.Render("No sockets open"))
+	}
+	for _, pid := range pids {
+		sockets := byPID[pid]
+		lines = append(lines, "", titleStyle.Render(fmt.Sprintf("%s (%d)", sockets[0].Process, pid)))
+		for _, s := range sockets {
+			lines = append(lines, "  "+m.socketLine(s))
+		}
+	}
+	return lines
+}
+
+// portLines lists the processes listening on the looked up port
+func (m *Model) portLines() []string {
+	v := m.sockets
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	if len(v.report.Sockets) == 0 {
+		return []string{"", labelStyle.Render(fmt.Sprintf("Nothing listens on port %d", v.port))}
+	}
+
+	lines := []string{""}
+	for _, s := range v.report.Sockets {
+		unit := s.Unit
+		if unit == "" {
+			unit = "no unit"
+		}
+		lines = append(lines, fmt.Sprintf("%-5s %-24s ", s.Protocol, s.Local)+
+			lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s (%d)", s.Process, s.PID))+
+			labelStyle.Render(" · "+unit))
+	}
+	return lines
+}
+
+// socketLine formats one socket, colored by state
+func (m *Model) socketLine(s types.Socket) string {
+	color := m.theme.Text
+	switch {
+	case s.Listening:
+		color = m.theme.Success
+	case s.State == "CLOSE-WAIT" || s.State == "TIME-WAIT":
+		color = m.theme.Warning
+	}
+
+	endpoint := s.Local
+	if endpoint == "" {
+		endpoint = "(unnamed)"
+	}
+	if s.Remote != "" {
+		endpoint += " → " + s.Remote
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%-11s", s.State)) +
+		fmt.Sprintf(" %-5s %s", s.Protocol, endpoint)
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Log statistics cover the last hour in one-minute buckets
+const (
+	statsWindow = time.Hour
+	statsBucket = time.Minute
+)
+
+// sparkBars are the sparkline levels, lowest first
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// statsLoadedMsg carries log statistics for the shown units
+type statsLoadedMsg struct {
+	stats *types.LogStats
+	err   error
+}
+
+// openStats shows the statistics overlay and computes it from the journal
+func (m *Model) openStats() tea.Cmd {
+	m.statsOpen = true
+	m.stats = nil
+	return m.loadStats()
+}
+
+// loadStats counts log lines, errors and repeated messages over the last
+// hour, ignoring the priority, boot and time filters of the logs pane
+func (m *Model) loadStats() tea.Cmd {
+	q := systemd.LogQuery{
+		Units:       m.activeLogUnits(),
+		MaxPriority: types.PriorityDebug,
+		PIDs:        m.logPIDs,
+	}
+	since := time.Now().Add(-statsWindow).Truncate(statsBucket)
+
+	return func() tea.Msg {
+		stats, err := m.logReader.GetLogStats(q, since, statsBucket)
+		return statsLoadedMsg{stats: stats, err: err}
+	}
+}
+
+// updateStats handles keys while the statistics overlay is open
+func (m *Model) updateStats(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "i", "q":
+		m.statsOpen = false
+		m.stats = nil
+	case "R":
+		m.stats = nil
+		return m.loadStats()
+	case "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// renderStats draws the statistics overlay in place of the logs
+func (m *Model) renderStats() string {
+	width := m.logViewport.Width - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("LOG STATS · last hour, per minute"))
+	sb.WriteString(labelStyle.Render("  [R]efresh • esc close"))
+	sb.WriteString("\n\n")
+
+	if m.stats == nil {
+		sb.WriteString(labelStyle.Render("Reading the journal..."))
+		return m.statsFrame(sb.String())
+	}
+	stats := m.stats
+
+	// Sparklines share the space left of the labels and totals
+	sparkWidth := max(10, width-26)
+	rows := []struct {
+		label  string
+		values []int
+		total  int
+		color  lipgloss.TerminalColor
+	}{
+		{"lines", stats.Lines, stats.Total, m.theme.Text},
+		{"errors", stats.Errors, stats.TotalErrors, m.theme.Error},
+		{"warnings", stats.Warnings, stats.TotalWarnings, m.theme.Warning},
+	}
+	for _, row := range rows {
+		values := downsample(row.values, sparkWidth)
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%-9s", row.label)))
+		sb.WriteString(lipgloss.NewStyle().Foreground(row.color).Render(sparkline(values)))
+		sb.WriteString(labelStyle.Render(fmt.Sprintf(" %6d  peak %d/min\n", row.total, peak(row.values))))
+	}
+	axisWidth := len(downsample(stats.Lines, sparkWidth))
+	sb.WriteString(labelStyle.Render(fmt.Sprintf("%-9s%s%*s\n", "",
+		stats.Start.Format("15:04"), axisWidth-5, "now")))
+
+	sb.WriteString("\n")
+	sb.WriteString(titleStyle.Render("TOP MESSAGES"))
+	sb.WriteString("\n")
+	if len(stats.TopMessages) == 0 {
+		sb.WriteString(labelStyle.Render("No log entries in the last hour"))
+	}
+	for _, msg := range stats.TopMessages {
+		style, icon := m.theme.priorityStyle(msg.Priority)
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%6d× ", msg.Count)))
+		sb.WriteString(style.Render(icon + truncate(firstLine(msg.Pattern), max(10, width-12))))
+		sb.WriteString("\n")
+	}
+
+	return m.statsFrame(sb.String())
+}
+
+// statsFrame wraps the overlay content in a border sized to the logs pane
+func (m *Model) statsFrame(content string) string {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Width(m.logViewport.Width - 2).
+		Height(m.logViewport.Height - 2).
+		MaxHeight(m.logViewport.Height).
+		Render(content)
+}
+
+// downsample merges adjacent buckets so values fit in width columns
+func downsample(values []int, width int) []int {
+	if len(values) <= width {
+		return values
+	}
+
+	merged := make([]int, width)
+	for i, v := range values {
+		merged[i*width/len(values)] += v
+	}
+	return merged
+}
+
+// sparkline renders values as bars scaled to the largest value
+func sparkline(values []int) string {
+	top := peak(values)
+
+	var sb strings.Builder
+	for _, v := range values {
+		switch {
+		case v == 0:
+			sb.WriteRune(' ')
+		case top == 0:
+			sb.WriteRune(sparkBars[0])
+		default:
+			sb.WriteRune(sparkBars[(v*(len(sparkBars)-1)+top-1)/top])
+		}
+	}
+	return sb.String()
+}
+
+// peak returns the largest value
+func peak(values []int) int {
+	top := 0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	return top
+}
+
+// firstLine returns the first line of a multi-line message
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
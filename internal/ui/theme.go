@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"sdtop/internal/config"
+	"sdtop/internal/types"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is the color palette used to render the UI
+type Theme struct {
+	Name       string
+	Text       lipgloss.TerminalColor // Regular text
+	Muted      lipgloss.TerminalColor // Timestamps, hints, inactive units
+	Accent     lipgloss.TerminalColor // Titles and the selected service
+	Success    lipgloss.TerminalColor // Running units, key hints
+	Warning    lipgloss.TerminalColor // Warnings, transitional states
+	Error      lipgloss.TerminalColor // Errors, failed units
+	Background lipgloss.TerminalColor // Title bars
+	Border     lipgloss.TerminalColor // Pane borders
+
+	// Palette colors unit tags in merged log views
+	Palette []lipgloss.TerminalColor
+}
+
+// builtinThemes are the themes shipped with sdtop
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Name:       "dark",
+		Text:       lipgloss.Color("252"),
+		Muted:      lipgloss.Color("240"),
+		Accent:     lipgloss.Color("170"),
+		Success:    lipgloss.Color("42"),
+		Warning:    lipgloss.Color("226"),
+		Error:      lipgloss.Color("196"),
+		Background: lipgloss.Color("235"),
+		Border:     lipgloss.Color("240"),
+		Palette:    colors("39", "208", "141", "48", "214", "81", "205", "118"),
+	},
+	"light": {
+		Name:       "light",
+		Text:       lipgloss.Color("235"),
+		Muted:      lipgloss.Color("244"),
+		Accent:     lipgloss.Color("127"),
+		Success:    lipgloss.Color("28"),
+		Warning:    lipgloss.Color("130"),
+		Error:      lipgloss.Color("160"),
+		Background: lipgloss.Color("254"),
+		Border:     lipgloss.Color("246"),
+		Palette:    colors("25", "130", "90", "28", "166", "31", "127", "64"),
+	},
+	"high-contrast": {
+		Name:       "high-contrast",
+		Text:       lipgloss.Color("15"),
+		Muted:      lipgloss.Color("250"),
+		Accent:     lipgloss.Color("14"),
+		Success:    lipgloss.Color("10"),
+		Warning:    lipgloss.Color("11"),
+		Error:      lipgloss.Color("9"),
+		Background: lipgloss.Color("0"),
+		Border:     lipgloss.Color("15"),
+		Palette:    colors("14", "11", "13", "10", "12", "9"),
+	},
+	"monochrome": {
+		Name:       "monochrome",
+		Text:       lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Accent:     lipgloss.NoColor{},
+		Success:    lipgloss.NoColor{},
+		Warning:    lipgloss.NoColor{},
+		Error:      lipgloss.NoColor{},
+		Background: lipgloss.NoColor{},
+		Border:     lipgloss.NoColor{},
+		Palette:    []lipgloss.TerminalColor{lipgloss.NoColor{}},
+	},
+}
+
+// colors converts color strings to a palette
+func colors(values ...string) []lipgloss.TerminalColor {
+	palette := make([]lipgloss.TerminalColor, len(values))
+	for i, v := range values {
+		palette[i] = lipgloss.Color(v)
+	}
+	return palette
+}
+
+// ThemeNames returns the names of all built-in and custom themes
+func ThemeNames(cfg *config.Config) []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range cfg.Themes {
+		if _, ok := builtinThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveTheme picks the theme to use. An explicitly requested theme always
+// wins; otherwise NO_COLOR and terminals without color support get the
+// monochrome theme, and "auto" follows the terminal background.
+func ResolveTheme(cfg *config.Config, requested string) (*Theme, error) {
+	name := requested
+	if name == "" {
+		if termenv.EnvNoColor() || lipgloss.ColorProfile() == termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.Ascii)
+			name = "monochrome"
+		} else {
+			name = cfg.Theme
+		}
+	}
+
+	if name == "" || name == "auto" {
+		name = "dark"
+		if !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+
+	return lookupTheme(cfg, name, 0)
+}
+
+// lookupTheme resolves a theme by name, following custom theme bases
+func lookupTheme(cfg *config.Config, name string, depth int) (*Theme, error) {
+	if depth > len(cfg.Themes) {
+		return nil, fmt.Errorf("theme %q has a circular base", name)
+	}
+
+	custom, ok := cfg.Themes[name]
+	if !ok {
+		builtin, ok := builtinThemes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown theme %q", name)
+		}
+		return &builtin, nil
+	}
+
+	base := custom.Base
+	if base == "" {
+		base = "dark"
+	}
+
+	var theme *Theme
+	if base == name {
+		// A custom theme overriding a built-in one of the same name
+		builtin, ok := builtinThemes[base]
+		if !ok {
+			return nil, fmt.Errorf("theme %q has a circular base", name)
+		}
+		theme = &builtin
+	} else {
+		var err error
+		theme, err = lookupTheme(cfg, base, depth+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	theme.Name = name
+	overrideColor(&theme.Text, custom.Text)
+	overrideColor(&theme.Muted, custom.Muted)
+	overrideColor(&theme.Accent, custom.Accent)
+	overrideColor(&theme.Success, custom.Success)
+	overrideColor(&theme.Warning, custom.Warning)
+	overrideColor(&theme.Error, custom.Error)
+	overrideColor(&theme.Background, custom.Background)
+	overrideColor(&theme.Border, custom.Border)
+	if len(custom.Palette) > 0 {
+		theme.Palette = colors(custom.Palette...)
+	}
+
+	return theme, nil
+}
+
+// overrideColor replaces a theme color if the config sets one
+func overrideColor(dst *lipgloss.TerminalColor, value string) {
+	if value != "" {
+		*dst = lipgloss.Color(value)
+	}
+}
+
+// listDelegate creates the service list delegate styled with the theme
+func (t *Theme) listDelegate() list.DefaultDelegate {
+	delegate := list.NewDefaultDelegate()
+
+	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.Foreground(t.Text)
+	delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.Foreground(t.Muted)
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(t.Accent).
+		BorderForeground(t.Accent).
+		Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(t.Text).
+		BorderForeground(t.Accent)
+	delegate.Styles.DimmedTitle = delegate.Styles.DimmedTitle.Foreground(t.Muted)
+	delegate.Styles.DimmedDesc = delegate.Styles.DimmedDesc.Foreground(t.Muted)
+
+	return delegate
+}
+
+// tagColor returns the palette color for the i-th unit in a merged view
+func (t *Theme) tagColor(i int) lipgloss.TerminalColor {
+	return t.Palette[i%len(t.Palette)]
+}
+
+// priorityStyle returns the line style and icon for a syslog priority
+func (t *Theme) priorityStyle(p types.Priority) (lipgloss.Style, string) {
+	style := lipgloss.NewStyle()
+
+	switch p {
+	case types.PriorityEmerg:
+		return style.Foreground(t.Error).Bold(true).Reverse(true), "‼ "
+	case types.PriorityAlert:
+		return style.Foreground(t.Error).Bold(true).Underline(true), "‼ "
+	case types.PriorityCrit:
+		return style.Foreground(t.Error).Bold(true), "✗ "
+	case types.PriorityErr:
+		return style.Foreground(t.Error), "✗ "
+	case types.PriorityWarning:
+		return style.Foreground(t.Warning), "⚠ "
+	case types.PriorityNotice:
+		return style.Foreground(t.Text).Bold(true), "• "
+	case types.PriorityDebug:
+		return style.Foreground(t.Muted).Faint(true), "  "
+	default:
+		return style.Foreground(t.Text), "  "
+	}
+}
+
+// stateStyle returns the color and symbol of a unit's active or sub state
+func (t *Theme) stateStyle(state string) (lipgloss.TerminalColor, string) {
+	switch state {
+	case "running", "active":
+		return t.Success, "●"
+	case "exited", "dead", "inactive":
+		return t.Muted, "○"
+	case "failed":
+		return t.Error, "✗"
+	default:
+		return t.Warning, "◐"
+	}
+}
+
+// sourceMarker labels entries written about a unit by systemd itself, so
+// lifecycle and crash messages stand out from the unit's own output
+func (t *Theme) sourceMarker(s types.LogSource) string {
+	switch s {
+	case types.SourceManager:
+		return lipgloss.NewStyle().Foreground(t.Accent).Italic(true).Render("[systemd]") + " "
+	case types.SourceCoredump:
+		return lipgloss.NewStyle().Foreground(t.Error).Bold(true).Reverse(true).Render("[coredump]") + " "
+	default:
+		return ""
+	}
+}
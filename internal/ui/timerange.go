@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bootsLoadedMsg is sent when the journal's boot list is loaded
+type bootsLoadedMsg struct {
+	boots []types.Boot
+}
+
+// loadBoots lists the boots recorded in the journal
+func (m *Model) loadBoots() tea.Msg {
+	boots, err := m.logReader.ListBoots()
+	if err != nil {
+		return systemd.ErrorMsg(fmt.Sprintf("Failed to list boots: %v", err))
+	}
+	return bootsLoadedMsg{boots: boots}
+}
+
+// cycleBoot steps through all boots → current → previous → ... → all boots
+func (m *Model) cycleBoot() tea.Cmd {
+	if len(m.boots) == 0 {
+		return func() tea.Msg { return statusMsgType("No boots recorded in the journal") }
+	}
+
+	switch {
+	case m.boot == nil:
+		m.boot = &m.boots[len(m.boots)-1]
+	case m.boot.Offset == m.boots[0].Offset:
+		m.boot = nil
+	default:
+		for i := range m.boots {
+			if m.boots[i].Offset == m.boot.Offset-1 {
+				m.boot = &m.boots[i]
+				break
+			}
+		}
+	}
+
+	return m.refreshLogs()
+}
+
+// timeRange is a since/until pair of the logs window
+type timeRange struct {
+	since, until time.Time
+}
+
+// jumpToRange shows logs in a temporary time range, such as the minutes
+// before a crash, remembering the range to go back to
+func (m *Model) jumpToRange(since, until time.Time) {
+	if m.jumpRange == nil {
+		m.jumpRange = &timeRange{since: m.since, until: m.until}
+	}
+	m.since, m.until = since, until
+}
+
+// restoreRange returns to the time range from before a jump, if any
+func (m *Model) restoreRange() {
+	if m.jumpRange != nil {
+		m.since, m.until = m.jumpRange.since, m.jumpRange.until
+		m.jumpRange = nil
+	}
+}
+
+// submitSeekTime moves the logs window to end at the entered time. An
+// empty answer returns to following the tail.
+func (m *Model) submitSeekTime(value string) tea.Cmd {
+	if value == "" || value == "now" {
+		m.until = time.Time{}
+	} else {
+		t, err := systemd.ParseTimeSpec(value, time.Now())
+		if err != nil {
+			m.errMsg = err.Error()
+			return nil
+		}
+		m.until = t
+	}
+
+	m.errMsg = ""
+	m.logViewport.SetContent("")
+	return m.refreshLogs()
+}
+
+// renderLogRange describes the active boot, time range and PID filter, if any
+func (m *Model) renderLogRange() string {
+	style := lipgloss.NewStyle().Foreground(m.theme.Accent)
+
+	var parts string
+	if m.boot != nil {
+		parts += fmt.Sprintf(" [boot %d]", m.boot.Offset)
+	}
+	if !m.since.IsZero() {
+		parts += " [since " + formatRangeTime(m.since) + "]"
+	}
+	if !m.until.IsZero() {
+		parts += " [until " + formatRangeTime(m.until) + "]"
+	}
+	if len(m.logPIDs) == 1 {
+		parts += fmt.Sprintf(" [pid %d]", m.logPIDs[0])
+	} else if len(m.logPIDs) > 1 {
+		parts += fmt.Sprintf(" [pid %d +%d]", m.logPIDs[0], len(m.logPIDs)-1)
+	}
+
+	if parts == "" {
+		return ""
+	}
+	return style.Render(parts)
+}
+
+// formatRangeTime formats a range bound, omitting the date for today
+func formatRangeTime(t time.Time) string {
+	now := time.Now()
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("2006-01-02 15:04")
+}
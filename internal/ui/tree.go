@@ -0,0 +1,37 @@
+package ui
+
+import "strings"
+
+// renderTree recursively writes a node and its children with box-drawing
+// connectors. line formats a node given its branch (indentation and
+// connector), so each tree decides what a node looks like.
+func renderTree[T any](sb *strings.Builder, node T, prefix string, isLast bool,
+	children func(T) []T, line func(node T, branch string) string) {
+	// Tree characters
+	var connector string
+	if prefix == "" {
+		connector = "┌─"
+	} else if isLast {
+		connector = "└─"
+	} else {
+		connector = "├─"
+	}
+
+	sb.WriteString(line(node, prefix+connector))
+	sb.WriteString("\n")
+
+	// Render children
+	childPrefix := prefix
+	if prefix == "" {
+		childPrefix = "  "
+	} else if isLast {
+		childPrefix = prefix + "   "
+	} else {
+		childPrefix = prefix + "│  "
+	}
+
+	kids := children(node)
+	for i, child := range kids {
+		renderTree(sb, child, childPrefix, i == len(kids)-1, children, line)
+	}
+}
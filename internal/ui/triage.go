@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sdtop/internal/systemd"
+	"sdtop/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// triageErrorLines is how many recent error lines are shown per unit
+const triageErrorLines = 3
+
+// triageView lists failed units with their exit details and last errors
+type triageView struct {
+	units    []types.FailedUnit
+	cursor   int             // Index of the highlighted unit
+	selected map[string]bool // Units picked for bulk actions
+	loading  bool
+	offset   int // First rendered line, to keep the cursor on screen
+}
+
+// triageLoadedMsg carries the failed units, and the outcome of the bulk
+// action that triggered the reload, if any
+type triageLoadedMsg struct {
+	units  []types.FailedUnit
+	status string
+	err    error
+}
+
+// openTriage shows the failed-unit triage screen
+func (m *Model) openTriage() tea.Cmd {
+	m.triage = &triageView{selected: map[string]bool{}, loading: true}
+	return m.loadTriage("")
+}
+
+// loadTriage lists failed units and their last error lines. The status is
+// reported once the list is loaded.
+func (m *Model) loadTriage(status string) tea.Cmd {
+	return func() tea.Msg {
+		units, err := m.manager.ListFailedUnits()
+		if err != nil {
+			return triageLoadedMsg{err: err}
+		}
+
+		for i := range units {
+			q := systemd.LogQuery{
+				Units:       []string{units[i].Name},
+				MaxPriority: types.PriorityErr,
+			}
+			units[i].LastErrors, _ = m.logReader.GetRecentLogs(q, triageErrorLines)
+		}
+		return triageLoadedMsg{units: units, status: status}
+	}
+}
+
+// handleTriageLoaded shows a fresh list of failed units, keeping the
+// cursor and selection on units that are still failed
+func (m *Model) handleTriageLoaded(msg triageLoadedMsg) tea.Cmd {
+	if m.triage == nil {
+		return nil
+	}
+	t := m.triage
+	t.loading = false
+
+	if msg.err != nil {
+		m.errMsg = fmt.Sprintf("Failed to list failed units: %v", msg.err)
+		return nil
+	}
+
+	current := ""
+	if t.cursor < len(t.units) {
+		current = t.units[t.cursor].Name
+	}
+
+	t.units = msg.units
+	t.cursor = 0
+	stillFailed := map[string]bool{}
+	for i, unit := range t.units {
+		stillFailed[unit.Name] = true
+		if unit.Name == current {
+			t.cursor = i
+		}
+	}
+	for name := range t.selected {
+		if !stillFailed[name] {
+			delete(t.selected, name)
+		}
+	}
+	m.scrollTriage()
+
+	if msg.status != "" {
+		return func() tea.Msg { return statusMsgType(msg.status) }
+	}
+	return nil
+}
+
+// updateTriage handles keys on the triage screen
+func (m *Model) updateTriage(msg tea.KeyMsg) tea.Cmd {
+	t := m.triage
+
+	switch msg.String() {
+	case "esc", "F", "q":
+		m.triage = nil
+	case "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		t.cursor = max(0, t.cursor-1)
+	case "down", "j":
+		t.cursor = max(0, min(len(t.units)-1, t.cursor+1))
+	case " ":
+		if t.cursor < len(t.units) {
+			name := t.units[t.cursor].Name
+			t.selected[name] = !t.selected[name]
+			if !t.selected[name] {
+				delete(t.selected, name)
+			}
+		}
+	case "a":
+		// Select all, or none when all are selected
+		if len(t.selected) == len(t.units) {
+			t.selected = map[string]bool{}
+		} else {
+			for _, unit := range t.units {
+				t.selected[unit.Name] = true
+			}
+		}
+	case "R":
+		return m.triageAction("Reset", m.manager.ResetFailedUnit)
+	case "r":
+		return m.triageAction("Restarted", m.manager.RestartService)
+	case "ctrl+r":
+		t.loading = true
+		return m.loadTriage("")
+	case "enter", "l":
+		// Jump to the unit's logs
+		if t.cursor < len(t.units) {
+			name := t.units[t.cursor].Name
+			m.triage = nil
+			m.focus = focusLogs
+			return m.selectService(name)
+		}
+	}
+	m.scrollTriage()
+	return nil
+}
+
+// triageRows is how many lines of units fit below the triage header
+func (m *Model) triageRows() int {
+	// The header and a blank line take two lines of the screen's height
+	return max(1, m.height-4-2)
+}
+
+// scrollTriage scrolls the triage screen so the highlighted unit's header
+// line stays visible
+func (m *Model) scrollTriage() {
+	t := m.triage
+	if t == nil {
+		return
+	}
+
+	// Each unit renders a header, its error lines or a placeholder, and a
+	// blank line
+	line := 0
+	for _, unit := range t.units[:min(t.cursor, len(t.units))] {
+		line += 2 + max(1, len(unit.LastErrors))
+	}
+
+	visible := m.triageRows()
+	if line < t.offset {
+		t.offset = line
+	} else if line >= t.offset+visible {
+		t.offset = line - visible + 1
+	}
+}
+
+// targets returns the selected units, or the highlighted one when
+// nothing is selected
+func (t *triageView) targets() []string {
+	var names []string
+	for _, unit := range t.units {
+		if t.selected[unit.Name] {
+			names = append(names, unit.Name)
+		}
+	}
+	if len(names) == 0 && t.cursor < len(t.units) {
+		names = append(names, t.units[t.cursor].Name)
+	}
+	return names
+}
+
+// triageAction applies an action to the target units and reloads the list
+func (m *Model) triageAction(verb string, action func(string) error) tea.Cmd {
+	names := m.triage.targets()
+	if len(names) == 0 {
+		return nil
+	}
+	m.triage.loading = true
+
+	return func() tea.Msg {
+		var failed []string
+		for _, name := range names {
+			if err := action(name); err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+			}
+		}
+
+		status := fmt.Sprintf("%s %d unit(s)", verb, len(names)-len(failed))
+		if len(failed) > 0 {
+			status += "; failed: " + strings.Join(failed, ", ")
+		}
+		return m.loadTriage(status)()
+	}
+}
+
+// renderTriage draws the triage screen in place of both panes
+func (m *Model) renderTriage() string {
+	t := m.triage
+	width := m.width - 4
+	height := m.height - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Error)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	nameStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Text)
+	gutter := lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▌")
+
+	header := titleStyle.Render(fmt.Sprintf("FAILED UNITS (%d)", len(t.units))) +
+		mutedStyle.Render("  space select • a all • R reset-failed • r restart • enter logs • ctrl+r reload • esc close")
+	if t.loading {
+		header += mutedStyle.Render("  loading...")
+	}
+
+	var lines []string
+	for i, unit := range t.units {
+		mark := " "
+		if i == t.cursor {
+			mark = gutter
+		}
+		check := "☐"
+		if t.selected[unit.Name] {
+			check = "☑"
+		}
+
+		details := []string{lipgloss.NewStyle().Foreground(m.theme.Error).Render(orDash(unit.Result))}
+		if exit := systemd.DescribeExit(unit.ExitCode, unit.ExitStatus); exit != "" {
+			details = append(details, exit)
+		}
+		if !unit.FailedAt.IsZero() {
+			details = append(details, fmt.Sprintf("at %s (%s ago)",
+				formatRangeTime(unit.FailedAt), time.Since(unit.FailedAt).Round(time.Second)))
+		}
+		if unit.NRestarts > 0 {
+			details = append(details, lipgloss.NewStyle().Foreground(m.theme.Warning).
+				Render(fmt.Sprintf("%d restarts", unit.NRestarts)))
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s %s  %s", mark, check,
+			nameStyle.Render(unit.Name), mutedStyle.Render("· ")+strings.Join(details, mutedStyle.Render(" · "))))
+
+		if len(unit.LastErrors) == 0 {
+			lines = append(lines, mutedStyle.Render("     (no error lines in the journal)"))
+		}
+		for _, entry := range unit.LastErrors {
+			style, icon := m.theme.priorityStyle(entry.Priority)
+			text := truncate(firstLine(entry.Message), max(10, width-20))
+			lines = append(lines, "     "+mutedStyle.Render(entry.Timestamp.Format("01-02 15:04:05"))+" "+
+				style.Render(icon+text))
+		}
+		lines = append(lines, "")
+	}
+	if len(t.units) == 0 && !t.loading {
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Success).Render("No failed units ✓"))
+	}
+
+	end := min(len(lines), t.offset+m.triageRows())
+	body := strings.Join(lines[min(t.offset, end):end], "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Error).
+		Width(m.width - 2).
+		Height(height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", body))
+}
+
+// orDash returns s, or "-" when it's empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}